@@ -2,19 +2,43 @@ package conf
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/BurntSushi/toml"
 	"github.com/vizn3r/go-lib/logger"
 	"gopkg.in/yaml.v3"
 )
 
+// Format of a config file
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// Extensions mapped to their format, compared lowercase
+var extensions = map[string]Format{
+	"json": FormatJSON,
+	"yaml": FormatYAML,
+	"yml":  FormatYAML,
+	"toml": FormatTOML,
+}
+
 var (
-	log    = logger.New("CONF", logger.Yellow)
-	global any
-	mu     sync.RWMutex
+	log = logger.NewLogger("CONF", logger.WithColor(logger.Yellow))
+	mu  sync.RWMutex
+	// Configs of the default namespace by type and of named namespaces
+	configs = map[reflect.Type]any{}
+	named   = map[string]any{}
 )
 
 func FindAndLoadConfig[T any](conf string) error {
@@ -48,73 +72,203 @@ func FindAndLoadConfig[T any](conf string) error {
 
 func decodeBytes[T any](data []byte, ftype string) (*T, error) {
 	var conf T
-	switch ftype {
-	case "json":
+	if reflect.TypeFor[T]().Kind() == reflect.Struct {
+		if err := applyDefaults(&conf); err != nil {
+			return nil, err
+		}
+	}
+	switch normalizeFormat(ftype) {
+	case FormatJSON:
 		parser := json.NewDecoder(strings.NewReader(string(data)))
 		parser.DisallowUnknownFields()
 		if err := parser.Decode(&conf); err != nil {
 			return nil, fmt.Errorf("couldn't decode config file %s", err)
 		}
 		return &conf, nil
-	case "yaml":
+	case FormatYAML:
 		parser := yaml.NewDecoder(strings.NewReader(string(data)))
 		parser.KnownFields(true)
 		if err := parser.Decode(&conf); err != nil {
 			return nil, fmt.Errorf("couldn't decode config file %s", err)
 		}
 		return &conf, nil
+	case FormatTOML:
+		meta, err := toml.Decode(string(data), &conf)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decode config file %s", err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("couldn't decode config file unknown field %q", undecoded[0].String())
+		}
+		return &conf, nil
 	default:
-		return nil, fmt.Errorf("unknown config file type")
+		return nil, fmt.Errorf("unknown config file type '%s', supported types are %s", ftype, supportedTypes())
 	}
 }
 
-func LoadFromBytes[T any](data []byte, ftype string) error {
+func normalizeFormat(ftype string) Format {
+	if f, ok := extensions[strings.ToLower(ftype)]; ok {
+		return f
+	}
+	return Format(ftype)
+}
+
+func supportedTypes() string {
+	types := make([]string, 0, len(extensions))
+	for ext := range extensions {
+		types = append(types, ext)
+	}
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
+
+// formatFromPath returns the format matching the extension of path
+func formatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("couldn't detect type of '%s' config file, no extension, use LoadConfigAs", path)
+	}
+	f, ok := extensions[strings.ToLower(ext)]
+	if !ok {
+		return "", fmt.Errorf("unknown config file type '%s', supported types are %s", ext, supportedTypes())
+	}
+	return f, nil
+}
+
+// load decodes data and applies environment overrides and validation
+func load[T any](data []byte, ftype string) (*T, error) {
 	conf, err := decodeBytes[T](data, ftype)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEnv(conf); err != nil {
+		return nil, err
+	}
+	if err := validate(conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func LoadFromBytes[T any](data []byte, ftype string) error {
+	conf, err := load[T](data, ftype)
 	if err != nil {
 		return err
 	}
 
 	mu.Lock()
-	global = conf
+	configs[reflect.TypeFor[T]()] = conf
 	mu.Unlock()
 
 	return nil
 }
 
 func LoadConfig[T any](path string) error {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return LoadConfigAs[T](path, format)
+}
+
+// LoadConfigAs is LoadConfig with the format given instead of taken from
+// the extension, for files like secrets mounts without one
+func LoadConfigAs[T any](path string, format Format) error {
+	data, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	err = LoadFromBytes[T](data, string(format))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func readConfig(path string) ([]byte, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("couldn't open '%s' config file", path)
+		return nil, fmt.Errorf("couldn't open '%s' config file", path)
 	}
 
 	if data == nil {
-		return fmt.Errorf("couldn't read '%s' config file", path)
+		return nil, fmt.Errorf("couldn't read '%s' config file", path)
 	}
+	return data, nil
+}
 
-	parts := strings.Split(path, ".")
-	ftype := strings.ToLower(parts[len(parts)-1])
-	err = LoadFromBytes[T](data, ftype)
+// LoadConfigNamed loads path into the namespace name, separate from the
+// default one and from other names, for several configs of one type
+func LoadConfigNamed[T any](name, path string) error {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := readConfig(path)
 	if err != nil {
 		return err
 	}
+	conf, err := load[T](data, string(format))
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	named[name] = conf
+	mu.Unlock()
 
 	return nil
 }
 
-func Get[T any]() *T {
+// GetNamed returns the config loaded with LoadConfigNamed under name
+func GetNamed[T any](name string) (*T, error) {
 	mu.RLock()
-	defer mu.RUnlock()
+	stored, ok := named[name]
+	mu.RUnlock()
 
-	if global == nil {
-		log.Fatal("Global config is not initialized")
-		return nil
+	if !ok {
+		return nil, fmt.Errorf("%w: no config named '%s'", ErrNotLoaded, name)
+	}
+	conf, ok := stored.(*T)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' loaded as %T, requested *%s", ErrTypeMismatch, name, stored, reflect.TypeFor[T]())
 	}
+	return conf, nil
+}
+
+var (
+	ErrNotLoaded    = errors.New("config is not loaded")
+	ErrTypeMismatch = errors.New("config type mismatch")
+)
+
+// GetE returns the config of type T in the default namespace, ErrNotLoaded
+// when none was loaded. Every type has its own config, so libraries and
+// the application don't clobber each other.
+func GetE[T any]() (*T, error) {
+	mu.RLock()
+	defer mu.RUnlock()
 
-	conf, ok := global.(*T)
+	stored, ok := configs[reflect.TypeFor[T]()]
 	if !ok {
-		log.Fatal("Global config type assertion failed")
-		return nil
+		return nil, fmt.Errorf("%w: no *%s", ErrNotLoaded, reflect.TypeFor[T]())
 	}
 
+	return stored.(*T), nil
+}
+
+// MustGet is GetE exiting the process through log.Fatal on errors
+func MustGet[T any]() *T {
+	conf, err := GetE[T]()
+	if err != nil {
+		log.Fatal(err)
+		return nil
+	}
 	return conf
 }
+
+// Get is MustGet, use GetE where a missing config can be handled
+func Get[T any]() *T {
+	return MustGet[T]()
+}
@@ -0,0 +1,322 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+	Port int    `json:"port" yaml:"port" toml:"port"`
+}
+
+// writeConfig writes data to name in a temporary directory and returns
+// the path
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// resetConfigs forgets the loaded configs when the test ends
+func resetConfigs(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		configs = map[reflect.Type]any{}
+		named = map[string]any{}
+		mu.Unlock()
+	})
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	const (
+		jsonData = `{"name": "api", "port": 8080}`
+		yamlData = "name: api\nport: 8080\n"
+	)
+	tests := []struct {
+		name   string
+		file   string
+		data   string
+		format Format // passed to LoadConfigAs when set
+		err    string
+	}{
+		{"json", "config.json", jsonData, "", ""},
+		{"yaml", "config.yaml", yamlData, "", ""},
+		{"yml", "config.yml", yamlData, "", ""},
+		{"uppercase", "config.YAML", yamlData, "", ""},
+		{"several dots", "config.production.json", jsonData, "", ""},
+		{"no extension", "config", yamlData, "", "no extension, use LoadConfigAs"},
+		{"no extension with format", "config", yamlData, FormatYAML, ""},
+		{"format beats extension", "config.txt", jsonData, FormatJSON, ""},
+		{"unknown type", "config.ini", "name=api", "", "unknown config file type 'ini', supported types are json, toml, yaml, yml"},
+		{"unknown format", "config", jsonData, Format("ini"), "unknown config file type 'ini'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfigs(t)
+			path := writeConfig(t, tt.file, tt.data)
+
+			var err error
+			if tt.format != "" {
+				err = LoadConfigAs[testConfig](path, tt.format)
+			} else {
+				err = LoadConfig[testConfig](path)
+			}
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Get[testConfig](); *got != (testConfig{Name: "api", Port: 8080}) {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	err := LoadConfig[testConfig](filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !strings.Contains(err.Error(), "couldn't open") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+type tomlConfig struct {
+	Title  string `toml:"title"`
+	Server struct {
+		Host    string        `toml:"host"`
+		Port    int           `toml:"port"`
+		Timeout time.Duration `toml:"timeout"`
+	} `toml:"server"`
+	Backends []struct {
+		Name   string   `toml:"name"`
+		Weight float64  `toml:"weight"`
+		Tags   []string `toml:"tags"`
+	} `toml:"backend"`
+}
+
+func TestLoadConfigTOML(t *testing.T) {
+	resetConfigs(t)
+	path := writeConfig(t, "config.toml", `
+title = "api"
+
+[server]
+host = "0.0.0.0"
+port = 8080
+timeout = "1m30s"
+
+[[backend]]
+name = "a"
+weight = 0.75
+tags = ["eu", "primary"]
+
+[[backend]]
+name = "b"
+weight = 0.25
+`)
+	if err := LoadConfig[tomlConfig](path); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Get[tomlConfig]()
+	if got.Title != "api" || got.Server.Host != "0.0.0.0" || got.Server.Port != 8080 || got.Server.Timeout != 90*time.Second {
+		t.Fatalf("got %+v", got)
+	}
+	if len(got.Backends) != 2 || got.Backends[0].Name != "a" || got.Backends[0].Weight != 0.75 ||
+		strings.Join(got.Backends[0].Tags, ",") != "eu,primary" || got.Backends[1].Name != "b" || got.Backends[1].Tags != nil {
+		t.Fatalf("backends %+v", got.Backends)
+	}
+}
+
+func TestLoadConfigTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"unknown key", "title = \"api\"\nname = \"x\"\n", `unknown field "name"`},
+		{"unknown nested key", "[server]\nhostname = \"x\"\n", `unknown field "server.hostname"`},
+		{"wrong type", "[server]\nport = \"80\"\n", "couldn't decode config file"},
+		{"bad duration", "[server]\ntimeout = \"soon\"\n", "couldn't decode config file"},
+		{"syntax", "title = \n", "couldn't decode config file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LoadFromBytes[tomlConfig]([]byte(tt.data), "toml")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+type otherConfig struct {
+	URL string `json:"url"`
+}
+
+func TestGetE(t *testing.T) {
+	resetConfigs(t)
+	if _, err := GetE[testConfig](); !errors.Is(err, ErrNotLoaded) || !strings.Contains(err.Error(), "*conf.testConfig") {
+		t.Fatalf("err = %v, want ErrNotLoaded naming the type", err)
+	}
+
+	if err := LoadFromBytes[testConfig]([]byte(`{"name": "api"}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetE[testConfig]()
+	if err != nil || got.Name != "api" {
+		t.Fatalf("GetE = %+v, %v", got, err)
+	}
+	if _, err := GetE[otherConfig](); !errors.Is(err, ErrNotLoaded) {
+		t.Fatalf("err = %v for a type that wasn't loaded", err)
+	}
+}
+
+func TestGetNamedErrors(t *testing.T) {
+	resetConfigs(t)
+	if err := LoadConfigNamed[testConfig]("primary", writeConfig(t, "db.json", `{"name": "db"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		get  func() error
+		is   error
+		msg  string
+	}{
+		{"missing name", func() error { _, err := GetNamed[testConfig]("replica"); return err }, ErrNotLoaded, "no config named 'replica'"},
+		{"other type", func() error { _, err := GetNamed[otherConfig]("primary"); return err }, ErrTypeMismatch,
+			"'primary' loaded as *conf.testConfig, requested *conf.otherConfig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.get()
+			if !errors.Is(err, tt.is) || !strings.Contains(err.Error(), tt.msg) {
+				t.Fatalf("err = %v, want %v with %q", err, tt.is, tt.msg)
+			}
+		})
+	}
+}
+
+func TestMustGet(t *testing.T) {
+	resetConfigs(t)
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	code := -1
+	log.SetExitFunc(func(c int) { code = c })
+	t.Cleanup(func() {
+		log.SetOutput(os.Stdout)
+		log.SetExitFunc(os.Exit)
+	})
+
+	if got := MustGet[testConfig](); got != nil || code != 1 {
+		t.Fatalf("MustGet = %v and exit code %d, want nil and 1", got, code)
+	}
+	if !strings.Contains(out.String(), "config is not loaded: no *conf.testConfig") {
+		t.Fatalf("logged %q", out.String())
+	}
+
+	code = -1
+	LoadFromBytes[testConfig]([]byte(`{"port": 1}`), "json")
+	if got := Get[testConfig](); got == nil || got.Port != 1 || code != -1 {
+		t.Fatalf("Get = %+v, exit code %d", got, code)
+	}
+}
+
+func TestConfigsPerType(t *testing.T) {
+	resetConfigs(t)
+	app := writeConfig(t, "app.json", `{"name": "api", "port": 8080}`)
+	db := writeConfig(t, "db.yaml", "url: postgres://db\n")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := LoadConfig[testConfig](app); err != nil {
+				t.Error(err)
+			}
+			if got, err := GetE[testConfig](); err == nil && got.Name != "api" {
+				t.Errorf("app config %+v", got)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := LoadConfig[otherConfig](db); err != nil {
+				t.Error(err)
+			}
+			if got, err := GetE[otherConfig](); err == nil && got.URL != "postgres://db" {
+				t.Errorf("db config %+v", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := Get[testConfig](); got.Name != "api" || got.Port != 8080 {
+		t.Fatalf("app config %+v", got)
+	}
+	if got := Get[otherConfig](); got.URL != "postgres://db" {
+		t.Fatalf("db config %+v", got)
+	}
+}
+
+func TestConfigsNamed(t *testing.T) {
+	resetConfigs(t)
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{"primary", "primary.json", `{"url": "postgres://primary"}`},
+		{"replica", "replica.yml", "url: postgres://replica\n"},
+	}
+
+	var wg sync.WaitGroup
+	for _, tt := range tests {
+		path := writeConfig(t, tt.file, tt.data)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := LoadConfigNamed[otherConfig](tt.name, path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	if err := LoadFromBytes[otherConfig]([]byte(`{"url": "postgres://default"}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	for _, tt := range tests {
+		got, err := GetNamed[otherConfig](tt.name)
+		if err != nil || got.URL != "postgres://"+tt.name {
+			t.Errorf("GetNamed(%q) = %+v, %v", tt.name, got, err)
+		}
+	}
+	if got := Get[otherConfig](); got.URL != "postgres://default" {
+		t.Fatalf("default namespace %+v, want it untouched by named configs", got)
+	}
+	if err := LoadConfigNamed[otherConfig]("bad", writeConfig(t, "bad.json", `{"host": "x"}`)); err == nil {
+		t.Fatal("unknown field accepted in a named config")
+	}
+	if _, err := GetNamed[otherConfig]("bad"); !errors.Is(err, ErrNotLoaded) {
+		t.Fatalf("failed load was stored: %v", err)
+	}
+}
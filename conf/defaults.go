@@ -0,0 +1,58 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// applyDefaults sets the fields of the struct conf points to from their
+// `default:"..."` tags. It runs before decoding so values present in the
+// file, zero or not, win.
+func applyDefaults(conf any) error {
+	_, err := applyDefaultsStruct(reflect.ValueOf(conf).Elem(), map[reflect.Type]bool{})
+	return err
+}
+
+// applyDefaultsStruct reports whether any field of v was set. walking
+// holds the struct types being walked, pointers back to one of them are
+// left nil so recursive types like linked lists end.
+func applyDefaultsStruct(v reflect.Value, walking map[reflect.Type]bool) (bool, error) {
+	set := false
+	t := v.Type()
+	walking[t] = true
+	defer delete(walking, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup("default")
+		fv := v.Field(i)
+		switch {
+		case ok:
+			if err := setFromString(fv, tag); err != nil {
+				return set, fmt.Errorf("couldn't apply default %q to %s: %s", tag, f.Name, err)
+			}
+			set = true
+		case fv.Kind() == reflect.Struct:
+			ok, err := applyDefaultsStruct(fv, walking)
+			if err != nil {
+				return set, err
+			}
+			set = set || ok
+		case fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct && !walking[fv.Type().Elem()]:
+			// Only allocate when something inside has a default
+			elem := reflect.New(fv.Type().Elem())
+			ok, err := applyDefaultsStruct(elem.Elem(), walking)
+			if err != nil {
+				return set, err
+			}
+			if ok {
+				fv.Set(elem)
+				set = true
+			}
+		}
+	}
+	return set, nil
+}
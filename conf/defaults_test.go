@@ -0,0 +1,161 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type defaultsConfig struct {
+	Name    string        `json:"name" yaml:"name" toml:"name" default:"api"`
+	Port    int           `json:"port" yaml:"port" toml:"port" default:"8080"`
+	Debug   bool          `json:"debug" yaml:"debug" toml:"debug" default:"true"`
+	Ratio   float64       `json:"ratio" yaml:"ratio" toml:"ratio" default:"0.5"`
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout" default:"30s"`
+	Hosts   []string      `json:"hosts" yaml:"hosts" toml:"hosts" default:"a,b"`
+	None    string        `json:"none" yaml:"none" toml:"none"`
+	Server  struct {
+		Host string `json:"host" yaml:"host" toml:"host" default:"localhost"`
+	} `json:"server" yaml:"server" toml:"server"`
+	TLS   *tlsDefaults   `json:"tls" yaml:"tls" toml:"tls"`
+	Cache *cacheDefaults `json:"cache" yaml:"cache" toml:"cache"`
+}
+
+type tlsDefaults struct {
+	Port int `json:"port" yaml:"port" toml:"port" default:"443"`
+}
+
+type cacheDefaults struct {
+	Dir string `json:"dir" yaml:"dir" toml:"dir"`
+}
+
+func TestDefaults(t *testing.T) {
+	defaults := func(c *defaultsConfig) {
+		c.Name, c.Port, c.Debug, c.Ratio, c.Timeout, c.Hosts = "api", 8080, true, 0.5, 30*time.Second, []string{"a", "b"}
+		c.Server.Host = "localhost"
+		c.TLS = &tlsDefaults{Port: 443}
+	}
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   func(c *defaultsConfig)
+	}{
+		{"empty json", "json", `{}`, func(c *defaultsConfig) {}},
+		{"empty yaml", "yaml", "{}\n", func(c *defaultsConfig) {}},
+		{"empty toml", "toml", "", func(c *defaultsConfig) {}},
+		{"values win", "json", `{"name": "web", "port": 9090, "hosts": ["c"]}`, func(c *defaultsConfig) {
+			c.Name, c.Port, c.Hosts = "web", 9090, []string{"c"}
+		}},
+		{"explicit json zeros", "json", `{"name": "", "port": 0, "debug": false, "ratio": 0, "hosts": []}`, func(c *defaultsConfig) {
+			c.Name, c.Port, c.Debug, c.Ratio, c.Hosts = "", 0, false, 0, []string{}
+		}},
+		{"explicit yaml zeros", "yaml", "port: 0\ndebug: false\nhosts: []\n", func(c *defaultsConfig) {
+			c.Port, c.Debug, c.Hosts = 0, false, []string{}
+		}},
+		{"explicit toml zeros", "toml", "port = 0\ndebug = false\nhosts = []\n", func(c *defaultsConfig) {
+			c.Port, c.Debug, c.Hosts = 0, false, []string{}
+		}},
+		{"nested", "yaml", "server:\n  host: example.com\n", func(c *defaultsConfig) { c.Server.Host = "example.com" }},
+		{"nested partially", "json", `{"server": {}}`, func(c *defaultsConfig) {}},
+		{"pointer", "json", `{"tls": {"port": 8443}}`, func(c *defaultsConfig) { c.TLS.Port = 8443 }},
+		{"null pointer", "json", `{"tls": null}`, func(c *defaultsConfig) { c.TLS = nil }},
+		{"pointer without defaults", "yaml", "cache:\n  dir: /tmp\n", func(c *defaultsConfig) { c.Cache = &cacheDefaults{Dir: "/tmp"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBytes[defaultsConfig]([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := &defaultsConfig{}
+			defaults(want)
+			tt.want(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDefaultsErrors(t *testing.T) {
+	type badInt struct {
+		Port int `json:"port" default:"http"`
+	}
+	type badNested struct {
+		Server struct {
+			Timeout time.Duration `default:"soon"`
+		}
+	}
+	type unsupported struct {
+		Ports []int `default:"1,2"`
+	}
+	tests := []struct {
+		name string
+		load func() error
+		want string
+	}{
+		{"int", func() error { return LoadFromBytes[badInt]([]byte(`{}`), "json") }, `couldn't apply default "http" to Port`},
+		{"nested duration", func() error { return LoadFromBytes[badNested]([]byte(`{}`), "json") }, `couldn't apply default "soon" to Timeout: not a duration`},
+		{"unsupported", func() error { return LoadFromBytes[unsupported]([]byte(`{}`), "json") }, "unsupported type []int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+type listNode struct {
+	Name string    `json:"name" default:"node"`
+	Next *listNode `json:"next"`
+}
+
+type treeA struct {
+	Name string `json:"name" default:"a"`
+	B    *treeB `json:"b"`
+}
+
+type treeB struct {
+	Port int    `json:"port" default:"80"`
+	A    *treeA `json:"a"`
+}
+
+func TestDefaultsRecursiveTypes(t *testing.T) {
+	list, err := decodeBytes[listNode]([]byte(`{"next": {"next": {}}}`), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// decoded nodes don't get defaults, only the ones there before decoding
+	if list.Name != "node" || list.Next == nil || list.Next.Name != "" || list.Next.Next == nil || list.Next.Next.Next != nil {
+		t.Fatalf("got %+v", list)
+	}
+
+	tree, err := decodeBytes[treeA]([]byte(`{}`), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Name != "a" || tree.B == nil || tree.B.Port != 80 || tree.B.A != nil {
+		t.Fatalf("got %+v and %+v", tree, tree.B)
+	}
+}
+
+func TestDefaultsNonStructTypes(t *testing.T) {
+	resetConfigs(t)
+	if err := LoadFromBytes[any]([]byte(`{"a": 1}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := *Get[any](); !reflect.DeepEqual(got, map[string]any{"a": float64(1)}) {
+		t.Fatalf("got %#v", got)
+	}
+	if err := LoadFromBytes[map[string]int]([]byte("a: 1\nb: 2\n"), "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if got := *Get[map[string]int](); got["a"] != 1 || got["b"] != 2 {
+		t.Fatalf("got %v", got)
+	}
+}
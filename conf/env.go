@@ -0,0 +1,194 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	envMu     sync.RWMutex
+	envPrefix string
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// SetEnvPrefix enables environment overrides for configs loaded afterwards,
+// APP_SERVER_PORT overrides Server.Port with prefix "APP". An empty prefix
+// turns them off again.
+func SetEnvPrefix(prefix string) {
+	envMu.Lock()
+	envPrefix = prefix
+	envMu.Unlock()
+}
+
+// ApplyEnv overrides the fields of the struct v points to with environment
+// variables named after the field path under prefix. Names come from the
+// json, yaml or toml tags, an `env:"NAME"` tag sets the full name.
+func ApplyEnv(v any, prefix string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("couldn't apply environment overrides to %T, not a pointer to a struct", v)
+	}
+	_, err := applyEnvStruct(rv.Elem(), envName(prefix), map[reflect.Type]bool{})
+	return err
+}
+
+func applyEnv(conf any) error {
+	envMu.RLock()
+	prefix := envPrefix
+	envMu.RUnlock()
+
+	if prefix == "" || reflect.ValueOf(conf).Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return ApplyEnv(conf, prefix)
+}
+
+// applyEnvStruct reports whether any field of v was set. walking holds the
+// struct types being walked, pointers back to one of them are skipped so
+// recursive types like linked lists end.
+func applyEnvStruct(v reflect.Value, prefix string, walking map[reflect.Type]bool) (bool, error) {
+	set := false
+	t := v.Type()
+	walking[t] = true
+	defer delete(walking, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := joinEnv(prefix, envName(fieldName(f)))
+		if tag := f.Tag.Get("env"); tag != "" {
+			name = tag
+		}
+
+		ok, err := applyEnvValue(v.Field(i), name, walking)
+		if err != nil {
+			return set, err
+		}
+		set = set || ok
+	}
+	return set, nil
+}
+
+func applyEnvValue(v reflect.Value, name string, walking map[reflect.Type]bool) (bool, error) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		return applyEnvStruct(v, name, walking)
+	case v.Kind() == reflect.Pointer && walking[v.Type().Elem()]:
+		return false, nil
+	case v.Kind() == reflect.Pointer:
+		// Only keep a new value when something inside it was set
+		elem := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			elem.Elem().Set(v.Elem())
+		}
+		ok, err := applyEnvValue(elem.Elem(), name, walking)
+		if ok && err == nil {
+			v.Set(elem)
+		}
+		return ok, err
+	}
+
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return false, nil
+	}
+	if err := setFromString(v, s); err != nil {
+		return false, fmt.Errorf("couldn't apply %s=%q: %s", name, s, err)
+	}
+	return true, nil
+}
+
+// setFromString parses s into v according to its kind
+func setFromString(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("not a duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("not a bool")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("not a number of type %s", v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("not a number of type %s", v.Type())
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("not a number of type %s", v.Type())
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		if s != "" {
+			items = strings.Split(s, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			slice.Index(i).SetString(strings.TrimSpace(item))
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// fieldName returns the name of f in the config file
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "yaml", "toml"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// envName uppercases s and replaces what isn't a letter or digit with _
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func joinEnv(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
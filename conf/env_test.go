@@ -0,0 +1,180 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type envConfig struct {
+	Name    string        `json:"name"`
+	Debug   bool          `yaml:"debug"`
+	Retries int8          `json:"retries"`
+	Limit   uint32        `json:"limit"`
+	Ratio   float64       `json:"ratio"`
+	Timeout time.Duration `json:"timeout"`
+	Hosts   []string      `json:"hosts"`
+	Secret  string        `json:"secret" env:"DB_PASSWORD"`
+	Server  struct {
+		Port     int `json:"port"`
+		MaxConns int `json:"max-conns"`
+	} `json:"server"`
+	TLS   *envTLS `json:"tls"`
+	Cache *envTLS `json:"cache"`
+	Plain int
+	quiet int
+}
+
+type envTLS struct {
+	Cert string `json:"cert_file"`
+	Key  string `json:"key_file"`
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(c *envConfig)
+	}{
+		{"nothing set", nil, func(c *envConfig) {}},
+		{"string", map[string]string{"APP_NAME": "api"}, func(c *envConfig) { c.Name = "api" }},
+		{"bool from the yaml tag", map[string]string{"APP_DEBUG": "true"}, func(c *envConfig) { c.Debug = true }},
+		{"numbers", map[string]string{"APP_RETRIES": "-3", "APP_LIMIT": "4000000000", "APP_RATIO": "0.5"}, func(c *envConfig) {
+			c.Retries, c.Limit, c.Ratio = -3, 4000000000, 0.5
+		}},
+		{"duration", map[string]string{"APP_TIMEOUT": "1m30s"}, func(c *envConfig) { c.Timeout = 90 * time.Second }},
+		{"slice", map[string]string{"APP_HOSTS": "a, b,c"}, func(c *envConfig) { c.Hosts = []string{"a", "b", "c"} }},
+		{"empty slice", map[string]string{"APP_HOSTS": ""}, func(c *envConfig) { c.Hosts = []string{} }},
+		{"env tag", map[string]string{"DB_PASSWORD": "s3cr3t", "APP_SECRET": "ignored"}, func(c *envConfig) { c.Secret = "s3cr3t" }},
+		{"nested", map[string]string{"APP_SERVER_PORT": "9090", "APP_SERVER_MAX_CONNS": "10"}, func(c *envConfig) {
+			c.Server.Port, c.Server.MaxConns = 9090, 10
+		}},
+		{"nil pointer", map[string]string{"APP_CACHE_CERT_FILE": "c.pem"}, func(c *envConfig) { c.Cache = &envTLS{Cert: "c.pem"} }},
+		{"pointer keeps other fields", map[string]string{"APP_TLS_KEY_FILE": "new.key"}, func(c *envConfig) { c.TLS.Key = "new.key" }},
+		{"field name", map[string]string{"APP_PLAIN": "7"}, func(c *envConfig) { c.Plain = 7 }},
+		{"unexported", map[string]string{"APP_QUIET": "7"}, func(c *envConfig) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got := &envConfig{TLS: &envTLS{Cert: "old.pem", Key: "old.key"}}
+			want := &envConfig{TLS: &envTLS{Cert: "old.pem", Key: "old.key"}}
+			tt.want(want)
+
+			if err := ApplyEnv(got, "app"); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got  %+v\nwant %+v", got, want)
+			}
+			if want.Cache == nil && got.Cache != nil {
+				t.Fatal("pointer allocated without a variable")
+			}
+		})
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	tests := []struct {
+		env  string
+		val  string
+		want string
+	}{
+		{"APP_DEBUG", "yes please", `couldn't apply APP_DEBUG="yes please": not a bool`},
+		{"APP_RETRIES", "300", `couldn't apply APP_RETRIES="300": not a number of type int8`},
+		{"APP_LIMIT", "-1", `couldn't apply APP_LIMIT="-1": not a number of type uint32`},
+		{"APP_RATIO", "half", `couldn't apply APP_RATIO="half": not a number of type float64`},
+		{"APP_TIMEOUT", "90", `couldn't apply APP_TIMEOUT="90": not a duration`},
+		{"APP_SERVER_PORT", "http", `couldn't apply APP_SERVER_PORT="http": not a number of type int`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, tt.val)
+			err := ApplyEnv(&envConfig{}, "APP")
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	if err := ApplyEnv(envConfig{}, "APP"); err == nil {
+		t.Fatal("no error for a struct that isn't a pointer")
+	}
+	t.Setenv("APP_X", "1")
+	if err := ApplyEnv(&struct{ X []int }{}, "APP"); err == nil || !strings.Contains(err.Error(), "unsupported type []int") {
+		t.Fatalf("err = %v for an unsupported slice", err)
+	}
+}
+
+func TestSetEnvPrefix(t *testing.T) {
+	resetConfigs(t)
+	t.Cleanup(func() { SetEnvPrefix("") })
+	t.Setenv("APP_PORT", "9090")
+
+	if err := LoadFromBytes[testConfig]([]byte(`{"name": "api", "port": 8080}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Get[testConfig]().Port; got != 8080 {
+		t.Fatalf("port = %d without a prefix, want the file's", got)
+	}
+
+	SetEnvPrefix("APP")
+	if err := LoadFromBytes[testConfig]([]byte(`{"name": "api", "port": 8080}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Get[testConfig](); got.Port != 9090 || got.Name != "api" {
+		t.Fatalf("got %+v, want the port overridden", got)
+	}
+}
+
+type envNode struct {
+	Name  string   `json:"name"`
+	Next  *envNode `json:"next"`
+	Child *envLeaf `json:"child"`
+}
+
+type envLeaf struct {
+	Port   int      `json:"port"`
+	Parent *envNode `json:"parent"`
+}
+
+func TestApplyEnvRecursiveTypes(t *testing.T) {
+	t.Setenv("APP_NAME", "root")
+	t.Setenv("APP_CHILD_PORT", "80")
+	t.Setenv("APP_NEXT_NAME", "skipped")
+
+	tests := []struct {
+		name string
+		node *envNode
+	}{
+		{"nil pointers", &envNode{}},
+		{"set pointers", &envNode{Next: &envNode{Name: "next"}}},
+		{"cycle", func() *envNode { n := &envNode{}; n.Next = n; return n }()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := tt.node.Next
+			if err := ApplyEnv(tt.node, "APP"); err != nil {
+				t.Fatal(err)
+			}
+			if tt.node.Name != "root" || tt.node.Child == nil || tt.node.Child.Port != 80 || tt.node.Child.Parent != nil {
+				t.Fatalf("got %+v", tt.node)
+			}
+			if tt.node.Next != next {
+				t.Fatal("pointer back to the walked type was changed")
+			}
+		})
+	}
+}
+
+func TestSetEnvPrefixNonStruct(t *testing.T) {
+	resetConfigs(t)
+	t.Cleanup(func() { SetEnvPrefix("") })
+	SetEnvPrefix("APP")
+	if err := LoadFromBytes[map[string]any]([]byte(`{"a": 1}`), "json"); err != nil {
+		t.Fatal(err)
+	}
+}
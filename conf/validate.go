@@ -0,0 +1,123 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validatable configs have Validate called after the validate tags were
+// checked, for rules spanning several fields
+type Validatable interface {
+	Validate() error
+}
+
+// validate checks the `validate:"..."` tags of the struct conf points to
+// and then its Validate method, all violations are returned together
+func validate(conf any) error {
+	var errs []error
+	if v := reflect.ValueOf(conf).Elem(); v.Kind() == reflect.Struct {
+		errs = validateStruct(v, "")
+	}
+	if len(errs) == 0 {
+		if c, ok := conf.(Validatable); ok {
+			if err := c.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string) []error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		path := fieldName(f)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		fv := v.Field(i)
+		if tag := f.Tag.Get("validate"); tag != "" {
+			errs = append(errs, validateField(fv, path, tag)...)
+		}
+		switch {
+		case fv.Kind() == reflect.Struct:
+			errs = append(errs, validateStruct(fv, path)...)
+		case fv.Kind() == reflect.Pointer && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct:
+			errs = append(errs, validateStruct(fv.Elem(), path)...)
+		}
+	}
+	return errs
+}
+
+// validateField applies the comma separated rules of tag to v
+func validateField(v reflect.Value, path, tag string) []error {
+	var errs []error
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "":
+		case "required":
+			if v.IsZero() {
+				errs = append(errs, fmt.Errorf("%s: required", path))
+			}
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: bad %s rule %q", path, name, arg))
+				continue
+			}
+			n, ok := number(v)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: %s rule needs a number, not %s", path, name, v.Type()))
+				continue
+			}
+			if name == "min" && n < limit {
+				errs = append(errs, fmt.Errorf("%s: %v is below min %s", path, v.Interface(), arg))
+			}
+			if name == "max" && n > limit {
+				errs = append(errs, fmt.Errorf("%s: %v is above max %s", path, v.Interface(), arg))
+			}
+		case "oneof":
+			options := strings.Fields(arg)
+			s := fmt.Sprint(v.Interface())
+			found := false
+			for _, o := range options {
+				if o == s {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errs = append(errs, fmt.Errorf("%s: %q is not one of %s", path, s, strings.Join(options, ", ")))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown validate rule %q", path, name))
+		}
+	}
+	return errs
+}
+
+// number returns v as a float64 when it is numeric
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type validateConfig struct {
+	Name   string  `json:"name" validate:"required"`
+	Port   int     `json:"port" validate:"min=1,max=65535"`
+	Ratio  float64 `json:"ratio" validate:"max=1"`
+	Mode   string  `json:"mode" validate:"oneof=dev prod"`
+	Server struct {
+		TLS *struct {
+			Cert string `json:"cert_file" validate:"required"`
+		} `json:"tls"`
+	} `json:"server"`
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		errs []string // each in the error, nil for a valid config
+	}{
+		{"valid", `{"name": "api", "port": 80, "ratio": 0.5, "mode": "dev"}`, nil},
+		{"required", `{"port": 80, "mode": "dev"}`, []string{"name: required"}},
+		{"min", `{"name": "api", "port": 0, "mode": "dev"}`, []string{"port: 0 is below min 1"}},
+		{"max", `{"name": "api", "port": 70000, "mode": "dev"}`, []string{"port: 70000 is above max 65535"}},
+		{"float max", `{"name": "api", "port": 80, "ratio": 1.5, "mode": "dev"}`, []string{"ratio: 1.5 is above max 1"}},
+		{"oneof", `{"name": "api", "port": 80, "mode": "staging"}`, []string{`mode: "staging" is not one of dev, prod`}},
+		{"nested path", `{"name": "api", "port": 80, "mode": "prod", "server": {"tls": {}}}`, []string{"server.tls.cert_file: required"}},
+		{"nil pointer skipped", `{"name": "api", "port": 80, "mode": "prod", "server": {}}`, nil},
+		{"all violations", `{"port": 0, "mode": "x", "server": {"tls": {}}}`, []string{
+			"name: required", "port: 0 is below min 1", `mode: "x" is not one of dev, prod`, "server.tls.cert_file: required",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfigs(t)
+			err := LoadFromBytes[validateConfig]([]byte(tt.data), "json")
+			if tt.errs == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), "invalid config: ") {
+				t.Fatalf("err = %v", err)
+			}
+			if got := strings.Count(err.Error(), "\n") + 1; got != len(tt.errs) {
+				t.Errorf("%d violations in %q, want %d", got, err, len(tt.errs))
+			}
+			for _, want := range tt.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("err = %q, want it to contain %q", err, want)
+				}
+			}
+			if _, err := GetE[validateConfig](); !errors.Is(err, ErrNotLoaded) {
+				t.Fatalf("invalid config was stored: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateBadRules(t *testing.T) {
+	type config struct {
+		A int    `validate:"min=x"`
+		B string `validate:"max=3"`
+		C int    `validate:"between=1 2"`
+	}
+	err := LoadFromBytes[config]([]byte(`{}`), "json")
+	for _, want := range []string{`A: bad min rule "x"`, "B: max rule needs a number, not string", `C: unknown validate rule "between"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+// checkedConfig has a rule spanning two fields
+type checkedConfig struct {
+	Name string `json:"name" validate:"required"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+}
+
+var errRange = errors.New("min is above max")
+
+func (c *checkedConfig) Validate() error {
+	if c.Min > c.Max {
+		return errRange
+	}
+	return nil
+}
+
+func TestValidatable(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		err   string
+		wraps error
+	}{
+		{"valid", `{"name": "a", "min": 1, "max": 2}`, "", nil},
+		{"custom rule", `{"name": "a", "min": 3, "max": 2}`, "invalid config: min is above max", errRange},
+		{"tags first", `{"min": 3, "max": 2}`, "invalid config: name: required", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfigs(t)
+			err := LoadFromBytes[checkedConfig]([]byte(tt.data), "json")
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("err = %v, want %s", err, tt.err)
+			}
+			if tt.wraps != nil && !errors.Is(err, tt.wraps) {
+				t.Fatalf("err = %v doesn't wrap the Validate error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !race
+
+// The race detector allocates on its own, the counts only hold without it
+
+package logger
+
+import "testing"
+
+// allocBudget is the most allocations a message may cost on the
+// synchronous path, raise it only with a reason in the commit message
+var allocBudget = []struct {
+	name string
+	opts []Option
+	log  func(lg *Logger)
+	max  float64
+}{
+	{"Info", nil, func(lg *Logger) { lg.Info("request handled") }, 0},
+	{"Info with args", nil, func(lg *Logger) { lg.Info("handled ", 42) }, 2},
+	{"Debug disabled", []Option{WithLevel(LevelInfo)}, func(lg *Logger) { lg.Debug("cache miss", 42) }, 0},
+	{"Debugf disabled", []Option{WithLevel(LevelInfo)}, func(lg *Logger) { lg.Debugf("miss %d", 42) }, 0},
+	{"DebugFunc disabled", []Option{WithLevel(LevelInfo)}, func(lg *Logger) { lg.DebugFunc(func() string { return "miss" }) }, 0},
+	{"Print highlighted", []Option{WithColorMode(ColorAlways)}, func(lg *Logger) { lg.Print("GET /users 200 OK in 12ms") }, 8},
+	{"JSON", []Option{WithJSON()}, func(lg *Logger) { lg.Info("request handled") }, 14},
+	{"fields", nil, func(lg *Logger) { lg.WithField("user", "ann").Info("request handled") }, 8},
+}
+
+func TestAllocBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation counts are checked without -short")
+	}
+	for _, tt := range allocBudget {
+		t.Run(tt.name, func(t *testing.T) {
+			lg := newBenchLogger(t, tt.opts...)
+			got := testing.AllocsPerRun(200, func() { tt.log(lg) })
+			if got > tt.max {
+				t.Fatalf("%.1f allocations per message, budget is %.0f", got, tt.max)
+			}
+		})
+	}
+}
+
+// Calls through Interface can't prove the variadic arguments don't escape,
+// so the caller allocates their slice, Nop itself allocates nothing
+func TestNopAllocs(t *testing.T) {
+	calls := []struct {
+		name string
+		log  func(l Interface)
+		max  float64
+	}{
+		{"Info", func(l Interface) { l.Info("request handled") }, 1},
+		{"Infof", func(l Interface) { l.Infof("handled %d", 42) }, 1},
+		{"Log", func(l Interface) { l.Log(LevelError, "failed ", 42) }, 1},
+		{"Infof no args", func(l Interface) { l.Infof("handled") }, 0},
+		{"Flush", func(l Interface) { l.Flush() }, 0},
+	}
+	l := Nop()
+	for _, tt := range calls {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testing.AllocsPerRun(100, func() { tt.log(l) }); got > tt.max {
+				t.Fatalf("%.1f allocations per call, budget is %.0f", got, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ansiRe matches CSI sequences like colors and OSC sequences like hyperlinks
+var ansiRe = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+
+// stripANSI removes terminal escape sequences from s
+func stripANSI(s string) string {
+	if !containsEsc(s) {
+		return s
+	}
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+func containsEsc(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleWidth returns the number of terminal columns s occupies, wide
+// east asian characters count twice
+func visibleWidth(s string) int {
+	n := 0
+	for _, r := range stripANSI(s) {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r >= 0x1100 && r <= 0x115f,
+		r >= 0x2e80 && r <= 0xa4cf && r != 0x303f,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x1f300 && r <= 0x1f64f,
+		r >= 0x1f900 && r <= 0x1f9ff,
+		r >= 0x20000 && r <= 0x3fffd:
+		return 2
+	}
+	return 1
+}
+
+// sanitize strips escape sequences and escapes control characters so s
+// renders as a single line, a trailing newline is dropped. Only hyperlinks
+// made by Hyperlink are kept, other OSC 8 sequences are stripped like any
+// escape.
+func sanitize(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	clean := true
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 && s[i] != '\t' || s[i] == 0x7f {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	open := false // inside a trusted link
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == 0x1b:
+			loc := ansiRe.FindStringIndex(s[i:])
+			if loc == nil || loc[0] != 0 {
+				b.WriteString(`\x1b`)
+				continue
+			}
+			if seq := s[i : i+loc[1]]; strings.HasPrefix(seq, "\x1b]8;") {
+				switch closing := strings.HasPrefix(seq, "\x1b]8;;"); {
+				case closing && open:
+					b.WriteString(seq)
+					open = false
+				case !closing && trustedLink(seq):
+					b.WriteString(seq)
+					open = true
+				}
+			}
+			i += loc[1] - 1
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// sanitizeLines is sanitize for each line of s, the newlines between them
+// are kept
+func sanitizeLines(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	if !strings.Contains(s, "\n") {
+		return sanitize(s)
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = sanitize(l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	link := Hyperlink("https://example.com", "docs")
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "hello world", "hello world"},
+		{"tab", "a\tb", "a\tb"},
+		{"trailing newline", "line\n", "line"},
+		{"newline", "user=x\nINFO forged", `user=x\nINFO forged`},
+		{"carriage return", "a\rb", `a\rb`},
+		{"control", "a\x00b\x7f", `a\x00b\x7f`},
+		{"color", "\x1b[31mred\x1b[0m", "red"},
+		{"lone escape", "a\x1bb", `a\x1bb`},
+		{"title", "\x1b]0;pwned\x07text", "text"},
+		{"injected link", "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", "click"},
+		{"injected link with id", "\x1b]8;id=1;https://evil.example\x07click\x1b]8;;\x07", "click"},
+		{"stray closer", "a\x1b]8;;\x1b\\b", "ab"},
+		{"trusted link", "see " + link, "see " + link},
+		{"trusted link and color", "\x1b[1m" + link, link},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.in); got != tt.want {
+				t.Errorf("sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeLines(t *testing.T) {
+	in := "first\x1b[31m\nsecond\r\n"
+	if got, want := sanitizeLines(in), "first\nsecond\\r"; got != want {
+		t.Fatalf("sanitizeLines(%q) = %q, want %q", in, got, want)
+	}
+}
+
+func TestHyperlinkURLControlCharacters(t *testing.T) {
+	link := Hyperlink("https://example.com/\x1b\\\x07x", "t")
+	if strings.Count(link, "\x1b") != 4 || strings.Contains(link, "\x07") {
+		t.Fatalf("control characters of the url kept in %q", link)
+	}
+}
+
+func TestLoggedLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"injected", "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", "click\n"},
+		{"helper", Hyperlink("https://example.com", "docs"), "docs (https://example.com)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			lg.SetHyperlinks(ColorNever)
+			lg.Info(tt.msg)
+			if got := strings.TrimPrefix(buf.String(), "[I]   "); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"abc", 3},
+		{ColorString(Red, "abc"), 3},
+		{"日本", 4},
+		{Hyperlink("https://example.com", "x"), 1},
+	}
+	for _, tt := range tests {
+		if got := visibleWidth(tt.s); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// AuditOption configures NewAuditWriter
+type AuditOption func(*AuditWriter)
+
+// AuditSync calls fsync after every line
+func AuditSync() AuditOption {
+	return func(w *AuditWriter) { w.sync = true }
+}
+
+// AuditWriter appends lines to a file as "<seq> <mac> <line>", where mac
+// is an HMAC-SHA256 over the previous mac, the sequence number and the
+// line. Editing, removing or reordering lines breaks the chain, see
+// VerifyAudit.
+type AuditWriter struct {
+	secret []byte
+	sync   bool
+
+	mu      sync.Mutex
+	f       *os.File
+	seq     uint64
+	prev    []byte
+	partial []byte
+}
+
+// Violation is an entry of an audit file that failed verification, Line
+// is 1-based
+type Violation struct {
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("line %d (seq %d): %s", v.Line, v.Seq, v.Reason)
+}
+
+// NewAuditWriter opens path for appending, continuing the chain of the
+// entries already in it. A partial last line, left by a crash in the
+// middle of a write, is removed first.
+func NewAuditWriter(path string, secret []byte, opts ...AuditOption) (*AuditWriter, error) {
+	last, size, partial, err := lastAuditEntry(path)
+	if err != nil {
+		return nil, err
+	}
+	if partial {
+		if err := os.Truncate(path, size); err != nil {
+			return nil, fmt.Errorf("couldn't remove partial entry of audit file %s: %w", path, err)
+		}
+	}
+	f, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &AuditWriter{secret: secret, f: f}
+	if last != nil {
+		w.seq, w.prev = last.seq, last.mac
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	return w, nil
+}
+
+// Write chains every complete line, a trailing partial line is kept until
+// the next Write or Close
+func (w *AuditWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.append(w.partial[:i]); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+// append writes one entry, must be called with w.mu held
+func (w *AuditWriter) append(line []byte) error {
+	seq := w.seq + 1
+	mac := auditMAC(w.secret, w.prev, seq, line)
+
+	b := getBuffer()
+	defer putBuffer(b)
+	b.WriteString(strconv.FormatUint(seq, 10))
+	b.WriteByte(' ')
+	b.WriteString(hex.EncodeToString(mac))
+	b.WriteByte(' ')
+	b.Write(line)
+	b.WriteByte('\n')
+
+	if _, err := w.f.Write(b.Bytes()); err != nil {
+		return fmt.Errorf("couldn't write audit entry %d: %w", seq, err)
+	}
+	if w.sync {
+		if err := w.f.Sync(); err != nil {
+			return fmt.Errorf("couldn't sync audit entry %d: %w", seq, err)
+		}
+	}
+	w.seq, w.prev = seq, mac
+	return nil
+}
+
+// Sync commits the file to stable storage
+func (w *AuditWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	return w.f.Sync()
+}
+
+// Close chains a pending partial line and closes the file
+func (w *AuditWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	var err error
+	if len(w.partial) > 0 {
+		err = w.append(w.partial)
+		w.partial = nil
+	}
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	w.f = nil
+	return err
+}
+
+func (w *AuditWriter) managed() {}
+
+func auditMAC(secret, prev []byte, seq uint64, line []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(prev)
+	h.Write([]byte(strconv.FormatUint(seq, 10)))
+	h.Write(line)
+	return h.Sum(nil)
+}
+
+type auditEntry struct {
+	seq  uint64
+	mac  []byte
+	line []byte
+}
+
+// parseAuditEntry splits "<seq> <mac> <line>"
+func parseAuditEntry(b []byte) (*auditEntry, error) {
+	seq, rest, ok := bytes.Cut(b, []byte{' '})
+	if !ok {
+		return nil, fmt.Errorf("missing sequence number")
+	}
+	mac, line, ok := bytes.Cut(rest, []byte{' '})
+	if !ok {
+		return nil, fmt.Errorf("missing mac")
+	}
+	n, err := strconv.ParseUint(string(seq), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("bad sequence number %q", seq)
+	}
+	sum, err := hex.DecodeString(string(mac))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("bad mac %q", mac)
+	}
+	return &auditEntry{seq: n, mac: sum, line: line}, nil
+}
+
+// lastAuditEntry returns the final complete entry of path, nil if it
+// doesn't exist or has none, and the size of the complete entries. partial
+// is set when a line without newline follows them.
+func lastAuditEntry(path string) (last *auditEntry, size int64, partial bool, err error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer f.Close()
+
+	var line []byte
+	r := bufio.NewReader(f)
+	for {
+		b, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			partial = len(b) > 0
+			break
+		}
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("couldn't read audit file %s: %w", path, err)
+		}
+		size += int64(len(b))
+		line = b[:len(b)-1]
+	}
+	if line == nil {
+		return nil, size, partial, nil
+	}
+	e, err := parseAuditEntry(line)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("couldn't continue audit file %s: %w", path, err)
+	}
+	return e, size, partial, nil
+}
+
+// VerifyAudit re-checks the chain of an audit file written with secret.
+// Each entry is checked against the mac stored on the line before it, so
+// a modified line is reported by itself and a removed one shows up as a
+// sequence gap on the entry after it.
+func VerifyAudit(path string, secret []byte) ([]Violation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		violations []Violation
+		prev       []byte
+		seq        uint64
+	)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		e, err := parseAuditEntry(sc.Bytes())
+		if err != nil {
+			violations = append(violations, Violation{Line: n, Seq: seq + 1, Reason: err.Error()})
+			seq++
+			prev = nil
+			continue
+		}
+		if e.seq != seq+1 {
+			violations = append(violations, Violation{Line: n, Seq: e.seq,
+				Reason: fmt.Sprintf("expected sequence number %d", seq+1)})
+		}
+		if !hmac.Equal(e.mac, auditMAC(secret, prev, e.seq, e.line)) {
+			violations = append(violations, Violation{Line: n, Seq: e.seq, Reason: "mac mismatch"})
+		}
+		seq, prev = e.seq, e.mac
+	}
+	if err := sc.Err(); err != nil {
+		return violations, fmt.Errorf("couldn't read audit file %s: %w", path, err)
+	}
+	return violations, nil
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var auditSecret = []byte("secret")
+
+func writeAudit(t *testing.T, path string, lines ...string) {
+	t.Helper()
+
+	w, err := NewAuditWriter(path, auditSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range lines {
+		if _, err := w.Write([]byte(l + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditChain(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		want   []string // reasons of the violations
+	}{
+		{
+			name:   "intact",
+			tamper: func(lines []string) []string { return lines },
+		},
+		{
+			name: "modified",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "second", "changed", 1)
+				return lines
+			},
+			want: []string{"line 2 (seq 2): mac mismatch"},
+		},
+		{
+			name:   "removed",
+			tamper: func(lines []string) []string { return append(lines[:1], lines[2:]...) },
+			want:   []string{"line 2 (seq 3): expected sequence number 2", "line 2 (seq 3): mac mismatch"},
+		},
+		{
+			name: "reordered",
+			tamper: func(lines []string) []string {
+				lines[0], lines[1] = lines[1], lines[0]
+				return lines
+			},
+			want: []string{
+				"line 1 (seq 2): expected sequence number 1", "line 1 (seq 2): mac mismatch",
+				"line 2 (seq 1): expected sequence number 3", "line 2 (seq 1): mac mismatch",
+				"line 3 (seq 3): expected sequence number 2", "line 3 (seq 3): mac mismatch",
+			},
+		},
+		{
+			name: "garbage",
+			tamper: func(lines []string) []string {
+				lines[2] = "not an entry"
+				return lines
+			},
+			want: []string{"line 3 (seq 3): bad sequence number \"not\""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			writeAudit(t, path, "first", "second", "third")
+
+			data, _ := os.ReadFile(path)
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			os.WriteFile(path, []byte(strings.Join(tt.tamper(lines), "\n")+"\n"), 0o644)
+
+			violations, err := VerifyAudit(path, auditSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditContinuesChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAudit(t, path, "first")
+	writeAudit(t, path, "second", "third")
+
+	violations, err := VerifyAudit(path, auditSecret)
+	if err != nil || len(violations) > 0 {
+		t.Fatalf("VerifyAudit = %v, %v", violations, err)
+	}
+	if _, err := VerifyAudit(path, []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := VerifyAudit(path, []byte("other")); len(v) != 3 {
+		t.Fatalf("wrong secret gave %d violations, want 3", len(v))
+	}
+}
+
+func TestAuditPartialLastLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		partial string
+	}{
+		{"cut in the payload", "3 0123abcd"},
+		{"cut in the sequence", "3"},
+		{"cut in the mac", "3 " + strings.Repeat("a", 20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			writeAudit(t, path, "first", "second")
+			f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+			f.WriteString(tt.partial)
+			f.Close()
+
+			writeAudit(t, path, "third")
+			violations, err := VerifyAudit(path, auditSecret)
+			if err != nil || len(violations) > 0 {
+				t.Fatalf("VerifyAudit = %v, %v", violations, err)
+			}
+			data, _ := os.ReadFile(path)
+			if n := strings.Count(string(data), "\n"); n != 3 || !strings.HasSuffix(string(data), " third\n") {
+				t.Fatalf("file = %q, want 3 complete entries", data)
+			}
+		})
+	}
+}
+
+func TestAuditCorruptLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	os.WriteFile(path, []byte("garbage\n"), 0o644)
+	if _, err := NewAuditWriter(path, auditSecret); err == nil {
+		t.Fatal("continued a chain ending in a corrupt complete entry")
+	}
+}
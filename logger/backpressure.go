@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Backpressure decides what happens when the log channel is full
+type Backpressure int
+
+const (
+	// Block the caller until there is room, the default
+	Block Backpressure = iota
+	// DropNewest discards the message being logged
+	DropNewest
+	// DropOldest discards the oldest queued message to make room
+	DropOldest
+)
+
+// SetBackpressure sets the policy used when the channel is full, the
+// channel size itself comes from LOGGER_BUFFER (100 by default)
+func (lg *Logger) SetBackpressure(policy Backpressure) {
+	lg.backpressure.Store(int32(policy))
+}
+
+// Dropped returns how many messages were discarded by the policy
+func (lg *Logger) Dropped() uint64 {
+	return lg.dropped.Load()
+}
+
+// QueueLen returns how many messages wait for the consumer goroutine
+func (lg *Logger) QueueLen() int {
+	return len(lg.logCh)
+}
+
+// QueueCap returns how many messages fit in the queue before the
+// backpressure policy applies
+func (lg *Logger) QueueCap() int {
+	return cap(lg.logCh)
+}
+
+// backlogWatch calls fn when the queue grows past threshold
+type backlogWatch struct {
+	threshold int
+	fn        func(len int)
+	above     atomic.Bool
+}
+
+// OnBacklog calls fn on its own goroutine once each time the queue grows
+// past threshold, it fires again after the queue drained back to threshold.
+// It replaces the previous callback, a nil fn removes it.
+func (lg *Logger) OnBacklog(threshold int, fn func(len int)) {
+	if fn == nil {
+		lg.backlog.Store(nil)
+		return
+	}
+	lg.backlog.Store(&backlogWatch{threshold: threshold, fn: fn})
+}
+
+// checkBacklog fires the callback on an upward crossing, called after
+// queueing a message
+func (lg *core) checkBacklog() {
+	w := lg.backlog.Load()
+	if w == nil {
+		return
+	}
+	if n := len(lg.logCh); n > w.threshold && w.above.CompareAndSwap(false, true) {
+		go w.fn(n)
+	}
+}
+
+// rearmBacklog lets the callback fire again once the queue drained, called
+// by the consumer
+func (lg *core) rearmBacklog() {
+	if w := lg.backlog.Load(); w != nil && w.above.Load() && len(lg.logCh) <= w.threshold {
+		w.above.Store(false)
+	}
+}
+
+// trySend queues m according to the policy. It returns false when the
+// caller should fall back to a blocking send.
+func (lg *core) trySend(m logMessage) bool {
+	select {
+	case lg.logCh <- m:
+		return true
+	default:
+	}
+
+	switch Backpressure(lg.backpressure.Load()) {
+	case DropNewest:
+		lg.drop()
+		return true
+	case DropOldest:
+		select {
+		case old := <-lg.logCh:
+			if old.done != nil {
+				// Someone waits on it, write it instead of dropping
+				lg.printer(old)
+				close(old.done)
+			} else {
+				lg.drop()
+			}
+		default:
+		}
+		select {
+		case lg.logCh <- m:
+		default:
+			lg.drop()
+		}
+		return true
+	}
+	return false
+}
+
+func (lg *core) drop() {
+	lg.dropped.Add(1)
+	lg.unreported.Add(1)
+}
+
+// reportDropped writes a single line announcing drops since the last one,
+// attributed to the module of the message just written
+func (lg *core) reportDropped(last logMessage) {
+	if n := lg.unreported.Swap(0); n > 0 {
+		lg.printer(lg.internalMessage(last, LevelWarn, fmt.Sprintf("(dropped %d messages)", n)))
+	}
+}
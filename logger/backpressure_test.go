@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockedWriter holds the first write until release is closed
+type blockedWriter struct {
+	syncBuffer
+	entered chan struct{}
+	release chan struct{}
+	first   bool
+}
+
+func newBlockedWriter() *blockedWriter {
+	return &blockedWriter{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	first := !w.first
+	w.first = true
+	w.mu.Unlock()
+	if first {
+		close(w.entered)
+		<-w.release
+	}
+	return w.syncBuffer.Write(p)
+}
+
+func TestBackpressure(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Backpressure
+		written []int
+		dropped uint64
+	}{
+		{"drop newest", DropNewest, []int{0, 1, 2}, 8},
+		{"drop oldest", DropOldest, []int{0, 9, 10}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newBlockedWriter()
+			lg := NewLogger("TEST", WithWriters(w), WithBufferSize(2), WithNoColor(), WithPrintTime(false))
+			lg.SetBackpressure(tt.policy)
+
+			lg.Info("m0")
+			<-w.entered
+			for i := 1; i <= 10; i++ {
+				lg.Infof("m%d", i)
+			}
+			if got := lg.Dropped(); got != tt.dropped {
+				t.Fatalf("Dropped = %d, want %d", got, tt.dropped)
+			}
+			close(w.release)
+			lg.Close()
+
+			var want strings.Builder
+			for i, n := range tt.written {
+				fmt.Fprintf(&want, "[TEST] [I]   m%d\n", n)
+				if i == 0 {
+					// announced after the message written while dropping
+					fmt.Fprintf(&want, "[TEST] [W] ? (dropped %d messages)\n", tt.dropped)
+				}
+			}
+			if w.String() != want.String() {
+				t.Fatalf("output:\n%s\nwant:\n%s", w, want.String())
+			}
+		})
+	}
+}
+
+func TestBackpressureBlock(t *testing.T) {
+	w := newBlockedWriter()
+	lg := NewLogger("TEST", WithWriters(w), WithBufferSize(1), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+
+	lg.Info("m0")
+	<-w.entered
+	lg.Info("m1")
+	sent := make(chan struct{})
+	go func() {
+		lg.Info("m2")
+		close(sent)
+	}()
+	select {
+	case <-sent:
+		t.Fatal("logging to a full queue didn't block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(w.release)
+	<-sent
+	if lg.Dropped() != 0 {
+		t.Fatalf("Dropped = %d with the Block policy", lg.Dropped())
+	}
+}
+
+// pausedWriter blocks writes while the test holds it
+type pausedWriter struct {
+	hold sync.Mutex
+	syncBuffer
+}
+
+func (w *pausedWriter) Write(p []byte) (int, error) {
+	w.hold.Lock()
+	defer w.hold.Unlock()
+	return w.syncBuffer.Write(p)
+}
+
+func TestOnBacklog(t *testing.T) {
+	tests := []struct {
+		name   string
+		bursts []int // messages logged while the writer is paused
+		calls  int
+	}{
+		{"below threshold", []int{3}, 0},
+		{"one crossing", []int{6}, 1},
+		{"stays above", []int{12}, 1},
+		{"two crossings", []int{6, 6}, 2},
+		{"drained below", []int{6, 2, 6}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &pausedWriter{}
+			lg := NewLogger("TEST", WithWriters(w), WithBufferSize(16), WithNoColor(), WithPrintTime(false))
+			defer lg.Close()
+			if lg.QueueCap() != 16 {
+				t.Fatalf("QueueCap = %d, want 16", lg.QueueCap())
+			}
+			var calls atomic.Int32
+			lens := make(chan int, 16)
+			lg.OnBacklog(3, func(n int) {
+				calls.Add(1)
+				lens <- n
+			})
+
+			for _, n := range tt.bursts {
+				w.hold.Lock()
+				for i := 0; i < n; i++ {
+					lg.Info("m")
+				}
+				if n > 3 && lg.QueueLen() <= 3 {
+					t.Fatalf("QueueLen = %d with a paused writer", lg.QueueLen())
+				}
+				w.hold.Unlock()
+				lg.Flush()
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for int(calls.Load()) < tt.calls && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond) // room for calls that shouldn't happen
+			if got := int(calls.Load()); got != tt.calls {
+				t.Fatalf("callback ran %d times, want %d", got, tt.calls)
+			}
+			for i := 0; i < tt.calls; i++ {
+				if n := <-lens; n <= 3 {
+					t.Errorf("callback got len %d, not above the threshold", n)
+				}
+			}
+		})
+	}
+}
+
+func TestOnBacklogCallbackDoesNotBlockLogging(t *testing.T) {
+	w := &pausedWriter{}
+	lg := NewLogger("TEST", WithWriters(w), WithBufferSize(16), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+	stuck := make(chan struct{})
+	defer close(stuck)
+	lg.OnBacklog(1, func(int) { <-stuck })
+
+	w.hold.Lock()
+	for i := 0; i < 4; i++ {
+		lg.Info("m")
+	}
+	w.hold.Unlock()
+	lg.Flush()
+	if got := strings.Count(w.String(), "m\n"); got != 4 {
+		t.Fatalf("wrote %d messages, want 4", got)
+	}
+}
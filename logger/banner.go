@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// BannerOption configures Logger.Banner
+type BannerOption func(*bannerConfig)
+
+type bannerConfig struct {
+	title   string
+	width   int
+	runtime bool
+}
+
+// BannerTitle sets the heading of the banner, the module by default
+func BannerTitle(title string) BannerOption {
+	return func(c *bannerConfig) { c.title = title }
+}
+
+// BannerWidth sets the outer width of the frame, 60 columns by default.
+// Longer values wrap inside it.
+func BannerWidth(n int) BannerOption {
+	return func(c *bannerConfig) { c.width = n }
+}
+
+// BannerRuntime appends the go version, GOOS/GOARCH and the PID
+func BannerRuntime() BannerOption {
+	return func(c *bannerConfig) { c.runtime = true }
+}
+
+// Banner prints fields in order as a startup block framed in the module
+// color. Writers without color get plain "key: value" lines instead.
+func (lg *Logger) Banner(fields []Field, opts ...BannerOption) {
+	if !lg.enabled(LevelPrint) {
+		return
+	}
+	c := &bannerConfig{title: lg.module, width: 60}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.runtime {
+		fields = append(fields[:len(fields):len(fields)],
+			Field{Key: "go", Value: runtime.Version()},
+			Field{Key: "platform", Value: runtime.GOOS + "/" + runtime.GOARCH},
+			Field{Key: "pid", Value: os.Getpid()},
+		)
+	}
+
+	// The frame is rendered as is, so redact before measuring and framing
+	var rules []redaction
+	if p := redactions.Load(); p != nil {
+		rules = *p
+	}
+	keys := make([]string, len(fields))
+	values := make([]string, len(fields))
+	keyWidth := 0
+	for i, f := range fields {
+		keys[i], values[i] = redactPair(rules, sanitize(f.Key), sanitize(fmt.Sprint(f.Value)))
+		keyWidth = max(keyWidth, visibleWidth(keys[i]))
+	}
+	// "│ " + key + "  " + value + " │"
+	valueWidth := max(c.width-keyWidth-6, 8)
+	inner := keyWidth + valueWidth + 2
+	mc := string(lg.color)
+
+	line := func(plain, framed string) {
+		m := lg.message(LevelPrint, plain)
+		m.fields = nil
+		m.framed = framed
+		m.internal = plain == ""
+		lg.emit(m)
+	}
+
+	title := redactString(rules, sanitize(c.title))
+	top := "╭─"
+	if title != "" {
+		top += " " + string(Bold) + title + string(Reset) + mc + " "
+	}
+	top += strings.Repeat("─", max(inner-visibleWidth(title)-2, 0)) + "─╮"
+	line(title, mc+top+string(Reset))
+
+	for i, key := range keys {
+		pad := strings.Repeat(" ", keyWidth-visibleWidth(key))
+		for j, part := range wrap(values[i], valueWidth) {
+			k := key + pad
+			if j > 0 {
+				k = strings.Repeat(" ", keyWidth)
+			}
+			fill := strings.Repeat(" ", valueWidth-visibleWidth(part))
+			framed := mc + "│" + string(Reset) + " " + string(Bold) + k + string(Reset) +
+				"  " + part + fill + " " + mc + "│" + string(Reset)
+			plain := "  " + k + "  " + part
+			if j == 0 {
+				plain = "  " + key + ": " + pad + part
+			}
+			line(strings.TrimRight(plain, " "), framed)
+		}
+	}
+	line("", mc+"╰"+strings.Repeat("─", inner+2)+"╯"+string(Reset))
+}
+
+// wrap splits s into chunks of at most width columns, breaking at spaces
+// when possible
+func wrap(s string, width int) []string {
+	if visibleWidth(s) <= width {
+		return []string{s}
+	}
+	var out []string
+	for visibleWidth(s) > width {
+		cut, w := 0, 0
+		space := -1
+		for i, r := range s {
+			rw := runeWidth(r)
+			if w+rw > width {
+				break
+			}
+			if r == ' ' {
+				space = i
+			}
+			w += rw
+			cut = i + len(string(r))
+		}
+		if space > 0 {
+			cut = space
+		}
+		out = append(out, s[:cut])
+		s = strings.TrimLeft(s[cut:], " ")
+	}
+	return append(out, s)
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// WithBatching buffers rendered lines per writer and writes them once
+// maxBytes are collected or maxDelay has passed, whichever comes first.
+// Flush, Close and Fatal write the buffers out. A failed write is reported
+// at Warn and the buffer starts over, so the writer is used again once it
+// recovers. Off by default.
+func WithBatching(maxBytes int, maxDelay time.Duration) Option {
+	return func(c *config) { c.batchBytes, c.batchDelay = maxBytes, maxDelay }
+}
+
+// batching reports whether lines are buffered
+func (lg *core) batching() bool {
+	return lg.batchBytes > 0
+}
+
+// bufferSinks gives the sinks without buffer one, terminals and writers
+// taking the level are left unbuffered. Must be called with wmu held.
+func (lg *core) bufferSinks() {
+	if !lg.batching() {
+		return
+	}
+	for i := range lg.sinks {
+		s := &lg.sinks[i]
+		if _, ok := s.w.(LevelWriter); !ok && !s.tty && s.buf == nil {
+			var w io.Writer = s.w
+			if s.lock != nil {
+				w = &serialWriter{Writer: s.w, mu: s.lock}
+			}
+			s.buf = &batchWriter{Writer: bufio.NewWriterSize(w, lg.batchBytes), w: w, failed: &lg.batchFailed}
+		}
+	}
+}
+
+// flushBatch writes out the buffered lines, must be called with wmu held
+func (lg *core) flushBatch() {
+	if !lg.batching() {
+		return
+	}
+	for _, s := range lg.sinks {
+		if s.buf != nil {
+			s.buf.Flush()
+		}
+	}
+}
+
+// batchWriter is the buffer of a sink. A bare bufio.Writer keeps failing
+// after the first error, this one drops what it couldn't write and starts
+// over.
+type batchWriter struct {
+	*bufio.Writer
+	w      io.Writer
+	err    error // first failure since the last report
+	failed *atomic.Bool
+}
+
+func (b *batchWriter) Write(p []byte) (int, error) {
+	n, err := b.Writer.Write(p)
+	b.check(err)
+	return n, err
+}
+
+func (b *batchWriter) Flush() error {
+	err := b.Writer.Flush()
+	b.check(err)
+	return err
+}
+
+func (b *batchWriter) check(err error) {
+	if err == nil {
+		return
+	}
+	lost := b.Buffered()
+	b.Reset(b.w)
+	if b.err == nil {
+		b.err = fmt.Errorf("couldn't write %s of batched lines: %w", Bytes(int64(lost)), err)
+	}
+	b.failed.Store(true)
+}
+
+// reportBatchErrors writes a line for each sink that failed to write its
+// buffer, attributed to the module of the message just written
+func (lg *core) reportBatchErrors(last logMessage) {
+	if !lg.batchFailed.Swap(false) {
+		return
+	}
+
+	var errs []error
+	lg.wmu.Lock()
+	for _, s := range lg.sinks {
+		if s.buf != nil && s.buf.err != nil {
+			errs = append(errs, s.buf.err)
+			s.buf.err = nil
+		}
+	}
+	lg.wmu.Unlock()
+
+	for _, err := range errs {
+		lg.printer(lg.internalMessage(last, LevelWarn, err.Error()))
+	}
+}
+
+// batchTicker returns the channel triggering periodic flushes, nil when
+// lines are not buffered
+func (lg *core) batchTicker() (<-chan time.Time, func()) {
+	if !lg.batching() || lg.batchDelay <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTicker(lg.batchDelay)
+	return t.C, t.Stop
+}
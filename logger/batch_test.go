@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyWriter fails while broken is set
+type flakyWriter struct {
+	mu     sync.Mutex
+	broken bool
+	writes int
+	data   strings.Builder
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.broken {
+		return 0, errors.New("disk full")
+	}
+	w.writes++
+	return w.data.Write(p)
+}
+
+func (w *flakyWriter) Writes() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writes
+}
+
+func (w *flakyWriter) set(broken bool) {
+	w.mu.Lock()
+	w.broken = broken
+	w.mu.Unlock()
+}
+
+func (w *flakyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.data.String()
+}
+
+func TestBatching(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBytes   int
+		n          int
+		wantWrites int
+	}{
+		{"off", 0, 10, 10},
+		{"one write", 1 << 10, 10, 1},
+		{"full buffer", 100, 10, 3}, // lines are 22 bytes
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &flakyWriter{}
+			lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false),
+				WithBatching(tt.maxBytes, time.Hour))
+			defer lg.Close()
+
+			for i := 0; i < tt.n; i++ {
+				lg.Info("message")
+			}
+			lg.Flush()
+			if got := strings.Count(w.String(), "\n"); got != tt.n {
+				t.Fatalf("%d lines written, want %d:\n%s", got, tt.n, w)
+			}
+			if got := w.Writes(); got != tt.wantWrites {
+				t.Fatalf("%d writes, want %d", got, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestBatchingTicker(t *testing.T) {
+	w := &flakyWriter{}
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithBatching(1<<10, 10*time.Millisecond))
+	defer lg.Close()
+
+	lg.Info("later")
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(w.String(), "later") {
+		if time.Now().After(deadline) {
+			t.Fatal("batch wasn't written after the delay")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchingRecoversAfterWriteError(t *testing.T) {
+	for _, sync := range []bool{true, false} {
+		w := &flakyWriter{}
+		buf := &syncBuffer{}
+		lg := NewLogger("TEST", WithWriters(w, Plain(buf)), WithSync(sync), WithNoColor(), WithPrintTime(false),
+			WithBatching(1<<10, time.Hour))
+
+		w.set(true)
+		lg.Info("lost")
+		lg.Flush()
+		lg.syncWriters()
+		lg.Flush() // the consumer reports after the next message
+
+		w.set(false)
+		lg.Info("recovered")
+		lg.Close()
+
+		if got := w.String(); strings.Contains(got, "lost") || !strings.Contains(got, "recovered") {
+			t.Errorf("sync %v: writer got %q, want only the line after it recovered", sync, got)
+		}
+		if got := buf.String(); !strings.Contains(got, "couldn't write") || !strings.Contains(got, "disk full") {
+			t.Errorf("sync %v: failure not reported, other writer got %q", sync, got)
+		}
+	}
+}
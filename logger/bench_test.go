@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func newBenchLogger(b testing.TB, opts ...Option) *Logger {
+	opts = append([]Option{WithWriters(io.Discard), WithSync(true), WithNoColor()}, opts...)
+	lg := NewLogger("BENCH", opts...)
+	b.Cleanup(lg.Close)
+	return lg
+}
+
+func BenchmarkInfo(b *testing.B) {
+	lg := newBenchLogger(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Info("request handled")
+	}
+}
+
+func BenchmarkInfoDisabled(b *testing.B) {
+	lg := newBenchLogger(b, WithLevel(LevelWarn))
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Info("request handled")
+	}
+}
+
+func BenchmarkDebugDisabled(b *testing.B) {
+	lg := newBenchLogger(b, WithLevel(LevelInfo))
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Debug("cache miss")
+	}
+}
+
+func BenchmarkPrintHighlighted(b *testing.B) {
+	lg := newBenchLogger(b, WithColorMode(ColorAlways))
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Print("GET /users 200 OK in 12ms")
+	}
+}
+
+func BenchmarkJSON(b *testing.B) {
+	lg := newBenchLogger(b, WithJSON())
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Info("request handled")
+	}
+}
+
+func BenchmarkWithFields(b *testing.B) {
+	lg := newBenchLogger(b).WithFields(map[string]any{"user": "ann", "id": 42})
+	b.ReportAllocs()
+	for b.Loop() {
+		lg.Info("request handled")
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	lg := NewLogger("BENCH", WithWriters(io.Discard), WithNoColor(), WithBufferSize(1024))
+	b.Cleanup(lg.Close)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			lg.Info("request handled")
+		}
+	})
+}
+
+func BenchmarkReportCaller(b *testing.B) {
+	tests := []struct {
+		name   string
+		report bool
+		level  LogLevel
+	}{
+		{"off", false, LevelPrint},
+		{"on", true, LevelPrint},
+		{"on filtered", true, LevelWarn},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			lg := newBenchLogger(b, WithLevel(tt.level))
+			lg.SetReportCaller(tt.report)
+			b.ReportAllocs()
+			for b.Loop() {
+				lg.Info("request handled")
+			}
+		})
+	}
+}
+
+func BenchmarkNop(b *testing.B) {
+	l := Nop()
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Infof("request %d handled", 42)
+	}
+}
+
+func BenchmarkDebugDisabledExpensive(b *testing.B) {
+	payload := make(map[string]int, 100)
+	for i := range 100 {
+		payload[fmt.Sprint("key", i)] = i
+	}
+	lg := newBenchLogger(b, WithLevel(LevelInfo))
+	b.Run("eager", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			lg.Debug(fmt.Sprintf("payload %v", payload))
+		}
+	})
+	b.Run("lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			lg.Debug(func() string { return fmt.Sprintf("payload %v", payload) })
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// SetReportCaller adds the file:line of the log call to every message,
+// the lookup is only done for messages passing the level filter
+func (lg *Logger) SetReportCaller(report bool) {
+	lg.reportCaller.Store(report)
+}
+
+// SetCallerSkip skips extra frames when reporting the caller, for helpers
+// wrapping the logger
+func (lg *Logger) SetCallerSkip(skip int) {
+	lg.callerSkip.Store(int32(skip))
+}
+
+// caller returns "dir/file.go:line" of the frame skip levels above its
+// own caller
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	return shortPath(file) + ":" + strconv.Itoa(line)
+}
+
+// SetStackTraceLevel appends the stack of the call site to messages at or
+// above level, LevelDisabled turns it off (the default)
+func (lg *Logger) SetStackTraceLevel(level LogLevel) {
+	lg.stackLevel.Store(int32(level))
+}
+
+// SetStackTraceDepth limits the number of frames in a stack trace, 32 by
+// default
+func (lg *Logger) SetStackTraceDepth(depth int) {
+	lg.stackDepth.Store(int32(depth))
+}
+
+// stack returns up to depth "func dir/file.go:line" frames starting skip
+// levels above its own caller
+func stack(skip, depth int) []string {
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	return formatFrames(pcs[:n])
+}
+
+func formatFrames(pcs []uintptr) []string {
+	if len(pcs) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		out = append(out, f.Function+" "+shortPath(f.File)+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// shortPath keeps only the last directory and the file name
+func shortPath(file string) string {
+	dir, name := filepath.Split(file)
+	return filepath.Join(filepath.Base(dir), name)
+}
+
+// goid returns the id of the calling goroutine
+func goid() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// callerLine returns the source line of this file that out reports as the
+// caller
+func callerLine(t *testing.T, out string) string {
+	t.Helper()
+
+	m := regexp.MustCompile(`logger/caller_test\.go:(\d+)`).FindStringSubmatch(out)
+	if m == nil {
+		t.Fatalf("no caller in this file reported: %q", out)
+	}
+	src, err := os.ReadFile("caller_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := strconv.Atoi(m[1])
+	return strings.Split(string(src), "\n")[n-1]
+}
+
+// logVia is a wrapper the way applications write them
+func logVia(lg *Logger, msg string) {
+	lg.Info(msg)
+}
+
+func TestReportCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		skip int
+		log  func(lg *Logger)
+		call string // on the reported line
+	}{
+		{"Info", 0, func(lg *Logger) { lg.Info("x") }, "lg.Info("},
+		{"Errorf", 0, func(lg *Logger) { lg.Errorf("%s", "x") }, "lg.Errorf("},
+		{"Infow", 0, func(lg *Logger) { lg.Infow("x", "k", 1) }, "lg.Infow("},
+		{"Log", 0, func(lg *Logger) { lg.Log(LevelWarn, "x") }, "lg.Log("},
+		{"WithField", 0, func(lg *Logger) { lg.WithField("k", 1).Warn("x") }, ".Warn("},
+		{"Err", 0, func(lg *Logger) { lg.Err(os.ErrClosed, "x") }, "lg.Err("},
+		{"WriterLevel", 0, func(lg *Logger) { lg.WriterLevel(LevelInfo).Write([]byte("x\n")) }, ".Write("},
+		{"StdLogger", 0, func(lg *Logger) { lg.StdLogger(LevelInfo).Print("x") }, ".Print("},
+		{"wrapper", 1, func(lg *Logger) { logVia(lg, "x") }, "logVia(lg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetReportCaller(true)
+			lg.SetCallerSkip(tt.skip)
+			tt.log(lg)
+			if line := callerLine(t, buf.String()); !strings.Contains(line, tt.call) {
+				t.Fatalf("reported line %q doesn't call %s", strings.TrimSpace(line), tt.call)
+			}
+		})
+	}
+}
+
+func TestReportCallerOutput(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.Info("off")
+	if strings.Contains(buf.String(), "caller_test.go") {
+		t.Fatalf("caller reported while disabled: %q", buf)
+	}
+
+	lg.SetReportCaller(true)
+	lg.SetFormat(FormatJSON)
+	lg.Info("on")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := entry["caller"].(string); !strings.HasPrefix(c, "logger/caller_test.go:") {
+		t.Fatalf("caller field = %q", entry["caller"])
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  LogLevel // of the stack traces
+		depth  int
+		log    func(lg *Logger)
+		frames int // -1 for at least one
+	}{
+		{"off", LevelDisabled, 32, func(lg *Logger) { lg.Error("x") }, 0},
+		{"below level", LevelError, 32, func(lg *Logger) { lg.Warn("x") }, 0},
+		{"at level", LevelError, 32, func(lg *Logger) { lg.Error("x") }, -1},
+		{"formatted", LevelError, 32, func(lg *Logger) { lg.Errorf("%s", "x") }, -1},
+		{"truncated", LevelWarn, 2, func(lg *Logger) { lg.Error("x") }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetStackTraceLevel(tt.level)
+			lg.SetStackTraceDepth(tt.depth)
+			tt.log(lg)
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			frames := lines[1:]
+			if tt.frames >= 0 && len(frames) != tt.frames || tt.frames < 0 && len(frames) == 0 {
+				t.Fatalf("%d frames:\n%s", len(frames), buf)
+			}
+			if len(frames) == 0 {
+				return
+			}
+			if !strings.HasPrefix(frames[0], "    github.com/vizn3r/go-lib/logger.TestStackTrace.") ||
+				!strings.Contains(frames[0], "logger/caller_test.go:") {
+				t.Fatalf("first frame %q isn't the test function", frames[0])
+			}
+		})
+	}
+}
+
+func TestStackTraceJSON(t *testing.T) {
+	lg, buf := newTestLogger(t, WithJSON())
+	lg.SetStackTraceLevel(LevelError)
+	lg.Error("x")
+
+	var entry struct{ Stack []string }
+	if err := json.Unmarshal([]byte(buf.String()), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Stack) == 0 || !strings.Contains(entry.Stack[0], "TestStackTraceJSON") {
+		t.Fatalf("stack field = %q", entry.Stack)
+	}
+}
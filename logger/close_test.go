@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCloseAbandonsWedgedWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(lg *Logger) error
+		err   error
+	}{
+		{"timeout", func(lg *Logger) error { return lg.CloseWithTimeout(20 * time.Millisecond) }, context.DeadlineExceeded},
+		{"canceled", func(lg *Logger) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return lg.CloseContext(ctx)
+		}, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newBlockedWriter()
+			defer close(w.release)
+			lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+
+			lg.Info("wedged")
+			<-w.entered
+			for i := 0; i < 3; i++ {
+				lg.Info("queued")
+			}
+
+			start := time.Now()
+			err := tt.close(lg)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("Close took %v", elapsed)
+			}
+			if !errors.Is(err, tt.err) || !strings.Contains(err.Error(), "abandoned 3 queued messages") {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestCloseWithTimeoutDrains(t *testing.T) {
+	w := &slowWriter{delay: time.Millisecond}
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+	for i := 0; i < 10; i++ {
+		lg.Info("x")
+	}
+	if err := lg.CloseWithTimeout(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(w.String(), "x\n"); got != 10 {
+		t.Fatalf("wrote %d messages, want 10", got)
+	}
+	if err := lg.CloseWithTimeout(time.Nanosecond); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+}
+
+func TestCloseConcurrent(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	lg.Info("before")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.Close()
+		}()
+	}
+	wg.Wait()
+	if buf.String() != "[TEST] [I]   before\n" {
+		t.Fatalf("output = %q", buf)
+	}
+}
+
+func TestCloseFromHook(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	lg.AddHook(func(level LogLevel, module, msg string, fields map[string]any) {
+		if msg == "closing" {
+			lg.Close()
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lg.Info("first")
+		lg.Warn("closing")
+		lg.Info("after")
+		lg.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close from a hook deadlocked")
+	}
+	if out := buf.String(); !strings.Contains(out, "first") || !strings.Contains(out, "closing") {
+		t.Fatalf("output = %q", out)
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// collapser suppresses consecutive copies of a message
+type collapser struct {
+	window   time.Duration
+	maxDelay time.Duration
+
+	mu         sync.Mutex
+	last       logMessage
+	seen       time.Time // when the last copy arrived
+	first      time.Time // when the first copy was suppressed
+	suppressed int
+	timer      *time.Timer
+}
+
+// SetCollapse suppresses consecutive identical messages (same level,
+// module and text) arriving within window of each other. Once a different
+// message arrives or the window passes, "previous message repeated N
+// times" is written. A summary is never held back longer than maxDelay. A
+// window of 0 turns collapsing off.
+func (lg *Logger) SetCollapse(window, maxDelay time.Duration) {
+	// Not control, the summary of the old collapser takes the write lock
+	lg.dispatch(logMessage{flush: true, ctrl: func() {
+		var next *collapser
+		if window > 0 {
+			if maxDelay <= 0 {
+				maxDelay = window
+			}
+			next = &collapser{window: window, maxDelay: maxDelay}
+		}
+		if c := lg.collapse.Swap(next); c != nil {
+			c.mu.Lock()
+			lg.flushCollapsed(c, true)
+			c.mu.Unlock()
+		}
+	}}, true)
+}
+
+// collapsed reports whether m repeats the previous message and is dropped
+func (lg *core) collapsed(m logMessage) bool {
+	c := lg.collapse.Load()
+	if c == nil || m.level >= LevelFatal {
+		return false
+	}
+
+	now := lg.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	same := c.last.msg == m.msg && c.last.level == m.level && c.last.module == m.module
+	if same && now.Sub(c.seen) < c.window && (c.suppressed == 0 || now.Sub(c.first) < c.maxDelay) {
+		if c.suppressed == 0 {
+			c.first = now
+			// the summary is due when the window ends without another
+			// copy, flushCollapsed moves it while copies keep coming
+			c.timer = time.AfterFunc(min(c.window, c.maxDelay), func() { lg.expireCollapsed(c) })
+		}
+		c.suppressed++
+		c.seen = now
+		return true
+	}
+
+	lg.flushCollapsed(c, true)
+	c.last, c.seen = m, now
+	return false
+}
+
+// expireCollapsed runs on the consumer to write a summary that waited too
+// long
+func (lg *core) expireCollapsed(c *collapser) {
+	lg.dispatch(logMessage{flush: true, ctrl: func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		lg.flushCollapsed(c, false)
+	}}, false)
+}
+
+// flushCollapsed writes the summary of suppressed copies, unless force is
+// false and neither the window nor maxDelay passed. Must be called with
+// c.mu held.
+func (lg *core) flushCollapsed(c *collapser, force bool) {
+	if c.suppressed == 0 {
+		return
+	}
+	now := lg.now()
+	if !force && now.Sub(c.seen) < c.window && now.Sub(c.first) < c.maxDelay {
+		c.timer.Reset(min(c.window-now.Sub(c.seen), c.maxDelay-now.Sub(c.first)))
+		return
+	}
+
+	c.timer.Stop()
+	lg.printer(lg.internalMessage(c.last, c.last.level,
+		fmt.Sprintf("previous message repeated %d times", c.suppressed)))
+	c.suppressed = 0
+	c.last = logMessage{} // the next copy starts over
+}
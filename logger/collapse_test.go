@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapse(t *testing.T) {
+	tests := []struct {
+		name string
+		msgs []string
+		want []string
+	}{
+		{
+			name: "distinct",
+			msgs: []string{"a", "b", "a"},
+			want: []string{"a", "b", "a"},
+		},
+		{
+			name: "interrupted",
+			msgs: []string{"a", "a", "a", "b"},
+			want: []string{"a", "previous message repeated 2 times", "b"},
+		},
+		{
+			name: "twice",
+			msgs: []string{"a", "a", "b", "b", "a"},
+			want: []string{"a", "previous message repeated 1 times", "b", "previous message repeated 1 times", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			lg.SetCollapse(time.Hour, time.Hour)
+			for _, m := range tt.msgs {
+				lg.Info(m)
+			}
+
+			var got []string
+			for _, l := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				got = append(got, strings.TrimPrefix(l, "[I]   "))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollapseSummaryAtWindowEnd(t *testing.T) {
+	for _, sync := range []bool{true, false} {
+		buf := &syncBuffer{}
+		lg := NewLogger("TEST", WithWriters(buf), WithSync(sync), WithNoColor(), WithPrintTime(false))
+		lg.SetCollapse(20*time.Millisecond, time.Minute)
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			lg.Info("same")
+		}
+
+		// well before maxDelay
+		for !strings.Contains(buf.String(), "previous message repeated 3 times") {
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("sync %v: no summary after the window:\n%s", sync, buf)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		lg.Close()
+	}
+}
+
+func TestCollapseConcurrentSet(t *testing.T) {
+	lg := NewLogger("TEST", WithWriters(&syncBuffer{}))
+	defer lg.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			lg.Info("x")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		lg.SetCollapse(time.Duration(i)*time.Millisecond, 0)
+	}
+	<-done
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ColorMode controls whether ANSI colors are emitted
+type ColorMode int
+
+const (
+	// ColorAuto enables colors for writers that are terminals unless
+	// NO_COLOR is set
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// SetColorMode overrides color detection
+func (lg *Logger) SetColorMode(mode ColorMode) {
+	lg.wmu.Lock()
+	lg.colorMode = mode
+	lg.wmu.Unlock()
+	lg.resolveColors()
+}
+
+// useColor resolves the mode against the environment and the writers
+func useColor(mode ColorMode, writers []io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	for _, w := range writers {
+		if !isTerminal(w) || !enableVT(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTerminal reports whether w is a character device such as a tty
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Color256 returns the foreground color n of the 256 color palette
+func Color256(n uint8) Color {
+	return Color(fmt.Sprintf("\033[38;5;%dm", n))
+}
+
+// ColorRGB returns a 24-bit foreground color for truecolor terminals
+func ColorRGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// Combine concatenates colors and styles, e.g. Combine(Bold, Red, BgWhite).
+// Reset clears all of them.
+func Combine(colors ...Color) Color {
+	var b strings.Builder
+	for _, c := range colors {
+		b.WriteString(string(c))
+	}
+	return Color(b.String())
+}
+
+// sgrRe matches a sequence of SGR escapes, what every Color is made of
+var sgrRe = regexp.MustCompile(`^(\x1b\[[0-9;]*m)+$`)
+
+// IsValidColor reports whether c consists only of SGR escape sequences,
+// like the constants, Color256, ColorRGB and Combine of them
+func IsValidColor(c Color) bool {
+	return sgrRe.MatchString(string(c))
+}
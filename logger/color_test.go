@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+	"testing"
+)
+
+// colorConstants parses the package source for every exported Color
+// constant, so new ones are checked without updating a list
+func colorConstants(t *testing.T) map[string]Color {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "log.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := map[string]Color{}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			v := spec.(*ast.ValueSpec)
+			if typ, ok := v.Type.(*ast.Ident); !ok || typ.Name != "Color" {
+				continue
+			}
+			for _, name := range v.Names {
+				if name.IsExported() {
+					colors[name.Name] = ""
+				}
+			}
+		}
+	}
+	// the parsed values are literals, take them from the compiled constants
+	// so escapes are resolved
+	for name, c := range exportedColors {
+		if _, ok := colors[name]; !ok {
+			t.Errorf("exportedColors lists %s which isn't a Color constant", name)
+		}
+		colors[name] = c
+	}
+	return colors
+}
+
+var exportedColors = map[string]Color{
+	"Reset": Reset, "Black": Black, "Red": Red, "Green": Green, "Yellow": Yellow,
+	"Blue": Blue, "Magenta": Magenta, "Cyan": Cyan, "White": White, "Grey": Grey,
+	"BrightRed": BrightRed, "BrightGreen": BrightGreen, "BrightYellow": BrightYellow,
+	"BrightBlue": BrightBlue, "BrightMagenta": BrightMagenta, "BrightCyan": BrightCyan,
+	"BrightWhite": BrightWhite, "BgBlack": BgBlack, "BgRed": BgRed, "BgGreen": BgGreen,
+	"BgYellow": BgYellow, "BgBlue": BgBlue, "BgMagenta": BgMagenta, "BgCyan": BgCyan,
+	"BgWhite": BgWhite, "BgGrey": BgGrey, "Bold": Bold, "Dim": Dim, "Italic": Italic,
+	"Underline": Underline,
+}
+
+func TestColorConstantsUnique(t *testing.T) {
+	seen := map[Color]string{}
+	for name, c := range colorConstants(t) {
+		if c == "" {
+			t.Errorf("%s is missing from exportedColors", name)
+			continue
+		}
+		if !IsValidColor(c) {
+			t.Errorf("%s = %q isn't a valid color", name, c)
+		}
+		if other, ok := seen[c]; ok {
+			t.Errorf("%s and %s are both %q", name, other, c)
+		}
+		seen[c] = name
+	}
+}
+
+func TestIsValidColor(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Color
+		want bool
+	}{
+		{"constant", Magenta, true},
+		{"256", Color256(208), true},
+		{"rgb", ColorRGB(1, 2, 3), true},
+		{"combined", Combine(Bold, Red, BgWhite), true},
+		{"empty", "", false},
+		{"text", "red", false},
+		{"trailing text", Red + "x", false},
+		{"cursor move", "\033[2J", false},
+		{"osc", "\033]0;title\a", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidColor(tt.c); got != tt.want {
+				t.Fatalf("IsValidColor(%q) = %v, want %v", tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    ColorMode
+		noColor string
+		want    bool
+	}{
+		{"always", ColorAlways, "", true},
+		{"always ignores NO_COLOR", ColorAlways, "1", true},
+		{"never", ColorNever, "", false},
+		{"auto not a terminal", ColorAuto, "", false},
+		{"auto NO_COLOR", ColorAuto, "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := useColor(tt.mode, []io.Writer{&bytes.Buffer{}}); got != tt.want {
+				t.Fatalf("useColor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorModeOutput(t *testing.T) {
+	log := func(lg *Logger) {
+		lg.Error("request FAIL with error")
+		lg.Print("GET /index OK")
+	}
+
+	colored, cbuf := newTestLogger(t, WithColor(Blue), WithColorMode(ColorAlways))
+	log(colored)
+	plain, pbuf := newTestLogger(t, WithColor(Blue), WithColorMode(ColorNever))
+	log(plain)
+
+	if !strings.Contains(cbuf.String(), "\033[") {
+		t.Fatalf("colored output has no escapes: %q", cbuf)
+	}
+	if strings.Contains(pbuf.String(), "\033") {
+		t.Fatalf("plain output has escapes: %q", pbuf)
+	}
+	if got := stripANSI(cbuf.String()); got != pbuf.String() {
+		t.Fatalf("colored output without escapes = %q, want %q", got, pbuf)
+	}
+}
+
+func TestColorConstructors(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Color
+		want string
+	}{
+		{"256", Color256(208), "\033[38;5;208m"},
+		{"256 min", Color256(0), "\033[38;5;0m"},
+		{"rgb", ColorRGB(255, 128, 0), "\033[38;2;255;128;0m"},
+		{"combine", Combine(Bold, Underline, Red, BgWhite), "\033[1m\033[4m\033[31m\033[47m"},
+		{"combine none", Combine(), ""},
+		{"bright", BrightMagenta, "\033[95m"},
+		{"background", BgGrey, "\033[100m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if string(tt.c) != tt.want {
+				t.Fatalf("got %q, want %q", tt.c, tt.want)
+			}
+		})
+	}
+}
+
+// Reset is SGR 0, which clears the color and every style modifier at once
+func TestResetClearsStyles(t *testing.T) {
+	if Reset != "\033[0m" {
+		t.Fatalf("Reset = %q", Reset)
+	}
+}
+
+func TestCombinedModuleColor(t *testing.T) {
+	style := Combine(Bold, Underline, ColorRGB(1, 2, 3))
+	lg, buf := newTestLogger(t, WithColor(style), WithColorMode(ColorAlways))
+	lg.Info("hi")
+	lg.Sub("SUB", Combine(Italic, BgBlue)).Info("hi")
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasPrefix(lines[0], string(style)+"[TEST]"+string(Reset)) {
+		t.Fatalf("module prefix = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], string(Combine(Italic, BgBlue))+"[TEST/SUB]"+string(Reset)) {
+		t.Fatalf("sub module prefix = %q", lines[1])
+	}
+}
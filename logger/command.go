@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// CommandWriter returns a writer for exec.Cmd.Stdout or Stderr logging
+// each line of the child at level. Escape sequences the child emits are
+// removed. Flush it after the command exits to log a last partial line.
+func CommandWriter(lg *Logger, level LogLevel) *LineWriter {
+	w := lg.WriterLevel(level)
+	w.strip = true
+	return w
+}
+
+// RunCommand runs the command logging its stdout at Info and its stderr at
+// Warn, followed by the exit status
+func (lg *Logger) RunCommand(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, stderr := CommandWriter(lg, LevelInfo), CommandWriter(lg, LevelWarn)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	err := cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		lg.logDepth(1, LevelInfo, name+" exited with status 0")
+	case errors.As(err, &exitErr):
+		lg.logfDepth(1, LevelError, "%s exited with status %d", name, exitErr.ExitCode())
+	default:
+		lg.logfDepth(1, LevelError, "couldn't run %s: %v", name, err)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't run %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestHelperProcess isn't a real test, RunCommand tests start the test
+// binary with it as a child process
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("LOGGER_HELPER_PROCESS") != "1" {
+		t.Skip("started by the RunCommand tests")
+	}
+	for i := 0; i < 50; i++ {
+		// split lines across writes to check partial lines are joined
+		fmt.Fprintf(os.Stdout, "\033[32mout")
+		fmt.Fprintf(os.Stderr, "err")
+		fmt.Fprintf(os.Stdout, " %d\033[0m\n", i)
+		fmt.Fprintf(os.Stderr, " %d\n", i)
+	}
+	fmt.Fprint(os.Stdout, "no newline")
+	os.Exit(len(os.Getenv("LOGGER_HELPER_EXIT")))
+}
+
+func TestCommandWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  LogLevel
+		writes []string
+		want   string
+	}{
+		{"lines", LevelInfo, []string{"a\nb\n"}, "[I]   a\n[I]   b\n"},
+		{"partial lines", LevelWarn, []string{"par", "tial\nrest", "\n"}, "[W] ? partial\n[W] ? rest\n"},
+		{"crlf", LevelInfo, []string{"dos\r\n"}, "[I]   dos\n"},
+		{"ansi", LevelInfo, []string{"\033[1;31mred\033[0m\n"}, "[I]   red\n"},
+		{"ansi split", LevelInfo, []string{"\033[3", "1mred\033[0m\n"}, "[I]   red\n"},
+		{"flushed tail", LevelError, []string{"done\nlast"}, "<E> ! done\n<E> ! last\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			w := CommandWriter(lg, tt.level)
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", s, n, err)
+				}
+			}
+			w.Flush()
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		exit   string // its length is the exit status of the child
+		status string
+	}{
+		{"success", "", "[I]   %s exited with status 0"},
+		{"failure", "xxx", "<E> ! %s exited with status 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOGGER_HELPER_PROCESS", "1")
+			t.Setenv("LOGGER_HELPER_EXIT", tt.exit)
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+
+			err := lg.RunCommand(context.Background(), os.Args[0], "-test.run=^TestHelperProcess$")
+			if (err != nil) != (tt.exit != "") {
+				t.Fatalf("err = %v", err)
+			}
+
+			var outs, errs []string
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			for _, line := range lines[:len(lines)-1] {
+				switch {
+				case strings.HasPrefix(line, "[I]   "):
+					outs = append(outs, strings.TrimPrefix(line, "[I]   "))
+				case strings.HasPrefix(line, "[W] ? "):
+					errs = append(errs, strings.TrimPrefix(line, "[W] ? "))
+				default:
+					t.Fatalf("unexpected line %q", line)
+				}
+			}
+			if len(errs) != 50 || len(outs) != 51 || outs[50] != "no newline" {
+				t.Fatalf("stdout %q\nstderr %q", outs, errs)
+			}
+			for i := 0; i < 50; i++ {
+				if outs[i] != fmt.Sprintf("out %d", i) || errs[i] != fmt.Sprintf("err %d", i) {
+					t.Fatalf("line %d torn: %q, %q", i, outs[i], errs[i])
+				}
+			}
+			if want := fmt.Sprintf(tt.status, os.Args[0]); lines[len(lines)-1] != want {
+				t.Fatalf("status line %q, want %q", lines[len(lines)-1], want)
+			}
+		})
+	}
+}
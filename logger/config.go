@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Option configures NewLogger
+type Option func(*config)
+
+// config holds the settings a logger is created with
+type config struct {
+	color      Color
+	writers    []io.Writer
+	level      LogLevel
+	bufferSize int
+	sync       bool
+	colorMode  ColorMode
+	printTime  bool
+	timeFormat string
+	format     Format
+	batchBytes int
+	batchDelay time.Duration
+
+	hostname bool
+	hostOver string // used instead of os.Hostname when set
+	pid      bool
+	static   map[string]any
+}
+
+// osHostname resolves the host field, replaceable in tests
+var osHostname = os.Hostname
+
+// fields returns the static fields: hostname, pid and then the custom ones
+// sorted by key
+func (c *config) fields() []Field {
+	var fields []Field
+	if c.hostname {
+		host := c.hostOver
+		if host == "" {
+			host, _ = osHostname()
+		}
+		fields = append(fields, Field{Key: "host", Value: host})
+	}
+	if c.pid {
+		fields = append(fields, Field{Key: "pid", Value: os.Getpid()})
+	}
+	if len(c.static) > 0 {
+		keys := make([]string, 0, len(c.static))
+		for k := range c.static {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		add := make([]Field, 0, len(keys))
+		for _, k := range keys {
+			add = append(add, Field{Key: k, Value: c.static[k]})
+		}
+		fields = mergeFields(fields, add)
+	}
+	return fields
+}
+
+// envConfig returns the defaults, overridden by the LOG_LEVEL and LOGGER_*
+// environment variables
+func envConfig() *config {
+	c := &config{
+		level:      LevelPrint,
+		bufferSize: 100,
+		colorMode:  ColorAuto,
+		printTime:  true,
+		timeFormat: defaultTimeFormat,
+	}
+
+	for _, env := range []string{"LOG_LEVEL", "LOGGER_LEVEL"} {
+		if l, err := ParseLevel(os.Getenv(env)); err == nil {
+			c.level = l
+		}
+	}
+	if sync, err := strconv.ParseBool(os.Getenv("LOGGER_SYNC")); err == nil {
+		c.sync = sync
+	}
+	if printTime, err := strconv.ParseBool(os.Getenv("LOGGER_TIME")); err == nil {
+		c.printTime = printTime
+	}
+	if colors, err := strconv.ParseBool(os.Getenv("LOGGER_COLORS")); err == nil {
+		c.colorMode = ColorNever
+		if colors {
+			c.colorMode = ColorAlways
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("LOGGER_BUFFER")); err == nil && n >= 0 {
+		c.bufferSize = n
+	}
+
+	if fast, _ := strconv.ParseBool(os.Getenv("LOGGER_FAST")); fast {
+		c.level = LevelPrint
+		c.sync = true
+		c.printTime = false
+		c.colorMode = ColorNever
+	}
+	return c
+}
+
+// WithColor sets the color of the module prefix
+func WithColor(color Color) Option {
+	return func(c *config) { c.color = color }
+}
+
+// WithWriters sets the destinations, stdout when there are none
+func WithWriters(writers ...io.Writer) Option {
+	return func(c *config) { c.writers = append(c.writers, writers...) }
+}
+
+// WithLevel sets the minimum level
+func WithLevel(level LogLevel) Option {
+	return func(c *config) { c.level = level }
+}
+
+// WithBufferSize sets how many messages can be queued before logging
+// blocks, see SetBackpressure
+func WithBufferSize(n int) Option {
+	return func(c *config) { c.bufferSize = max(0, n) }
+}
+
+// WithSync creates the logger without consumer goroutine, see SetSync
+func WithSync(sync bool) Option {
+	return func(c *config) { c.sync = sync }
+}
+
+// WithColorMode sets the color mode, see SetColorMode
+func WithColorMode(mode ColorMode) Option {
+	return func(c *config) { c.colorMode = mode }
+}
+
+// WithNoColor disables colors for every writer
+func WithNoColor() Option {
+	return WithColorMode(ColorNever)
+}
+
+// WithPrintTime toggles the timestamp in text output
+func WithPrintTime(print bool) Option {
+	return func(c *config) { c.printTime = print }
+}
+
+// WithTimeFormat sets the timestamp layout, see SetTimeFormat
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		if layout != "" {
+			c.timeFormat = layout
+		}
+	}
+}
+
+// WithFormat sets the output format, see SetFormat
+func WithFormat(format Format) Option {
+	return func(c *config) { c.format = format }
+}
+
+// WithJSON writes JSON lines instead of text
+func WithJSON() Option {
+	return WithFormat(FormatJSON)
+}
+
+// WithHostname adds a host field to every message, resolved once
+func WithHostname() Option {
+	return func(c *config) { c.hostname = true }
+}
+
+// WithHostnameOverride adds a host field with name instead of the
+// resolved hostname, for containers with generated hostnames
+func WithHostnameOverride(name string) Option {
+	return func(c *config) { c.hostname, c.hostOver = true, name }
+}
+
+// WithPID adds a pid field to every message
+func WithPID() Option {
+	return func(c *config) { c.pid = true }
+}
+
+// WithStaticFields adds fields to every message, fields of WithFields and
+// of the call win on key collision
+func WithStaticFields(fields map[string]any) Option {
+	return func(c *config) {
+		if c.static == nil {
+			c.static = map[string]any{}
+		}
+		for k, v := range fields {
+			c.static[k] = v
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionsApplyToFirstMessage(t *testing.T) {
+	fixed := time.Date(2026, 10, 14, 9, 5, 7, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(lg *Logger)
+		want string
+	}{
+		{"defaults", nil, func(lg *Logger) { lg.Info("hi") }, "[TEST] 2026/10/14 09:05:07 [I]   hi\n"},
+		{"level", []Option{WithLevel(LevelWarn)}, func(lg *Logger) { lg.Info("hi"); lg.Warn("w") }, "[TEST] 2026/10/14 09:05:07 [W] ? w\n"},
+		{"no time", []Option{WithPrintTime(false)}, func(lg *Logger) { lg.Info("hi") }, "[TEST] [I]   hi\n"},
+		{"time format", []Option{WithTimeFormat(time.Kitchen)}, func(lg *Logger) { lg.Info("hi") }, "[TEST] 9:05AM [I]   hi\n"},
+		{"empty time format", []Option{WithTimeFormat("")}, func(lg *Logger) { lg.Info("hi") }, "[TEST] 2026/10/14 09:05:07 [I]   hi\n"},
+		{"json", []Option{WithJSON()}, func(lg *Logger) { lg.Info("hi") }, `{"time":"2026-10-14T09:05:07Z","level":"info","module":"TEST","msg":"hi"}` + "\n"},
+		{"color", []Option{WithColor(Blue), WithColorMode(ColorAlways), WithPrintTime(false)}, func(lg *Logger) { lg.Info("hi") },
+			"\033[34m[TEST]\033[0m\033[90m \033[34m[I]   \033[0mhi\n"},
+		{"static fields", []Option{WithPrintTime(false), WithHostnameOverride("web-1"), WithStaticFields(map[string]any{"env": "prod"})},
+			func(lg *Logger) { lg.Info("hi") }, "[TEST] [I]   hi host=web-1 env=prod\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			opts := append([]Option{WithWriters(buf), WithNoColor()}, tt.opts...)
+			lg := NewLogger("TEST", opts...)
+			lg.SetClock(func() time.Time { return fixed })
+			tt.log(lg)
+			lg.Close()
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		opts []Option
+		want config
+	}{
+		{"defaults", nil, nil, config{level: LevelPrint, bufferSize: 100, colorMode: ColorAuto, printTime: true}},
+		{"level", map[string]string{"LOG_LEVEL": "warn"}, nil, config{level: LevelWarn, bufferSize: 100, colorMode: ColorAuto, printTime: true}},
+		{"logger level wins", map[string]string{"LOG_LEVEL": "warn", "LOGGER_LEVEL": "debug"}, nil, config{level: LevelDebug, bufferSize: 100, colorMode: ColorAuto, printTime: true}},
+		{"invalid values", map[string]string{"LOG_LEVEL": "loud", "LOGGER_BUFFER": "-1", "LOGGER_SYNC": "maybe"}, nil, config{level: LevelPrint, bufferSize: 100, colorMode: ColorAuto, printTime: true}},
+		{"switches", map[string]string{"LOGGER_SYNC": "1", "LOGGER_TIME": "false", "LOGGER_COLORS": "true", "LOGGER_BUFFER": "0"}, nil,
+			config{level: LevelPrint, sync: true, colorMode: ColorAlways, printTime: false}},
+		{"fast", map[string]string{"LOGGER_FAST": "1", "LOG_LEVEL": "error", "LOGGER_COLORS": "1"}, nil, config{level: LevelPrint, bufferSize: 100, sync: true, colorMode: ColorNever}},
+		{"options win", map[string]string{"LOG_LEVEL": "error", "LOGGER_SYNC": "1"}, []Option{WithLevel(LevelInfo), WithSync(false), WithBufferSize(-5)},
+			config{level: LevelInfo, colorMode: ColorAuto, printTime: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"LOG_LEVEL", "LOGGER_LEVEL", "LOGGER_SYNC", "LOGGER_TIME", "LOGGER_COLORS", "LOGGER_BUFFER", "LOGGER_FAST"} {
+				t.Setenv(env, tt.env[env])
+			}
+			c := envConfig()
+			for _, o := range tt.opts {
+				o(c)
+			}
+			got := config{level: c.level, bufferSize: c.bufferSize, sync: c.sync, colorMode: c.colorMode, printTime: c.printTime}
+			if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			if c.timeFormat != defaultTimeFormat {
+				t.Fatalf("time format = %q", c.timeFormat)
+			}
+		})
+	}
+}
+
+func TestNewDeprecated(t *testing.T) {
+	t.Setenv("LOGGER_TIME", "0")
+	a, b := &syncBuffer{}, &syncBuffer{}
+	lg := New("OLD", Red, a, b)
+	if got := lg.QueueCap(); got != 100 {
+		t.Fatalf("QueueCap = %d", got)
+	}
+	lg.Info("hi")
+	lg.Close()
+	for _, buf := range []*syncBuffer{a, b} {
+		if got := stripANSI(buf.String()); got != "[OLD] [I]   hi\n" {
+			t.Fatalf("got %q", got)
+		}
+	}
+}
+
+func TestStaticFields(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(lg *Logger)
+		want string
+	}{
+		{"hostname override", []Option{WithHostnameOverride("web-1")}, func(lg *Logger) { lg.Info("hi") }, "[I]   hi host=web-1"},
+		{"static sorted", []Option{WithStaticFields(map[string]any{"region": "eu", "env": "prod"})}, func(lg *Logger) { lg.Info("hi") },
+			"[I]   hi env=prod region=eu"},
+		{"merged static maps", []Option{WithStaticFields(map[string]any{"env": "dev", "a": 1}), WithStaticFields(map[string]any{"env": "prod"})},
+			func(lg *Logger) { lg.Info("hi") }, "[I]   hi a=1 env=prod"},
+		{"static overrides host", []Option{WithHostnameOverride("web-1"), WithStaticFields(map[string]any{"host": "lb"})},
+			func(lg *Logger) { lg.Info("hi") }, "[I]   hi host=lb"},
+		{"WithFields wins", []Option{WithHostnameOverride("web-1"), WithStaticFields(map[string]any{"env": "prod"})},
+			func(lg *Logger) { lg.WithField("env", "canary").Info("hi") }, "[I]   hi host=web-1 env=canary"},
+		{"call wins", []Option{WithStaticFields(map[string]any{"env": "prod", "user": "none"})},
+			func(lg *Logger) { lg.WithField("user", "ann").Infow("hi", "user", "bob") }, "[I]   hi env=prod user=bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, tt.opts...)
+			lg.SetPrintModule(false)
+			tt.log(lg)
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPIDField(t *testing.T) {
+	lg, buf := newTestLogger(t, WithPID(), WithJSON())
+	lg.Info("hi")
+	if want := fmt.Sprintf(`"pid":%d`, os.Getpid()); !strings.Contains(buf.String(), want) {
+		t.Fatalf("%s has no %s", buf, want)
+	}
+}
+
+func TestHostnameResolvedOnce(t *testing.T) {
+	calls := 0
+	old := osHostname
+	osHostname = func() (string, error) {
+		calls++
+		return "box", nil
+	}
+	defer func() { osHostname = old }()
+
+	lg, buf := newTestLogger(t, WithHostname())
+	lg.SetPrintModule(false)
+	lg.Info("a")
+	lg.Sub("S", "").Info("b")
+	lg.WithField("k", 1).Info("c")
+	if calls != 1 {
+		t.Fatalf("hostname resolved %d times", calls)
+	}
+	if want := "[I]   a host=box\n[I]   b host=box\n[I]   c host=box k=1\n"; buf.String() != want {
+		t.Fatalf("got %q, want %q", buf, want)
+	}
+}
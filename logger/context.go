@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Extractor returns the fields a context carries, like a request ID
+type Extractor func(ctx context.Context) map[string]any
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []Extractor
+)
+
+// ContextExtractor registers fn for WithContext, extractors run in the
+// order they were registered and later ones win on key collision
+func ContextExtractor(fn Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// WithContext returns a derived logger with the fields the registered
+// extractors find in ctx
+func (lg *Logger) WithContext(ctx context.Context) *Logger {
+	extractorsMu.RLock()
+	fns := extractors
+	extractorsMu.RUnlock()
+
+	child := lg
+	for _, fn := range fns {
+		if fields := fn(ctx); len(fields) > 0 {
+			child = child.WithFields(fields)
+		}
+	}
+	if child == lg {
+		child = lg.derive()
+	}
+	return child
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying lg
+func NewContext(ctx context.Context, lg *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, lg)
+}
+
+// FromContext returns the logger stored by NewContext, or a logger that
+// discards everything when there is none. Like Nop, its Fatal still exits
+// the process.
+func FromContext(ctx context.Context) *Logger {
+	if lg, ok := ctx.Value(contextKey{}).(*Logger); ok && lg != nil {
+		return lg
+	}
+	return discard()
+}
+
+var discard = sync.OnceValue(func() *Logger {
+	return NewLogger("", WithWriters(io.Discard), WithSync(true), WithLevel(LevelDisabled))
+})
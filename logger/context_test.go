@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	ctx := NewContext(context.Background(), lg)
+	if got := FromContext(ctx); got != lg {
+		t.Fatalf("FromContext = %p, want the stored logger %p", got, lg)
+	}
+	if got := FromContext(context.Background()); got != discard() {
+		t.Fatal("FromContext without logger didn't return the discard logger")
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	type key struct{}
+	ContextExtractor(func(ctx context.Context) map[string]any {
+		if id, ok := ctx.Value(key{}).(string); ok {
+			return map[string]any{"request_id": id}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"with id", context.WithValue(context.Background(), key{}, "abc"), "[TEST] [I]   handled request_id=abc\n"},
+		{"without id", context.Background(), "[TEST] [I]   handled\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.WithContext(tt.ctx).Info("handled")
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Fatal of both inert loggers exits with code 1
+func TestInertLoggersExitOnFatal(t *testing.T) {
+	code := -1
+	exit := func(c int) { code = c }
+
+	lg := FromContext(context.Background())
+	lg.SetExitFunc(exit)
+	defer lg.SetExitFunc(nil)
+	prev := nopExit
+	nopExit = exit
+	defer func() { nopExit = prev }()
+
+	tests := []struct {
+		name  string
+		fatal func()
+	}{
+		{"FromContext Fatal", func() { lg.Fatal("boom") }},
+		{"FromContext Fatalf", func() { lg.Fatalf("%s", "boom") }},
+		{"Nop Fatal", func() { Nop().Fatal("boom") }},
+		{"Nop Fatalf", func() { Nop().Fatalf("%s", "boom") }},
+	}
+	for _, tt := range tests {
+		code = -1
+		tt.fatal()
+		if code != 1 {
+			t.Errorf("%s: exit code %d, want 1", tt.name, code)
+		}
+	}
+}
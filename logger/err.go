@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Err logs err at error level with an optional message, a nil err is a
+// no-op so it can end deferred cleanup. Errors with a StackTrace method
+// (like github.com/pkg/errors) get their frames attached.
+func (lg *Logger) Err(err error, msg ...any) {
+	if err == nil || !lg.enabled(LevelError) && lg.ring.Load() == nil {
+		return
+	}
+
+	m := lg.message(LevelError, fmt.Sprint(msg...))
+	m.err = err
+	skip := 1 + int(lg.callerSkip.Load())
+	if lg.reportCaller.Load() {
+		m.caller = caller(skip)
+	}
+	if pcs := errorStack(err); len(pcs) > 0 {
+		m.stack = formatFrames(pcs)
+	} else if sl := LogLevel(lg.stackLevel.Load()); sl != LevelDisabled && LevelError >= sl {
+		m.stack = stack(skip, int(lg.stackDepth.Load()))
+	}
+	if !lg.enabled(LevelError) {
+		lg.record(m)
+		return
+	}
+	lg.emit(m)
+}
+
+// errorText renders "msg: err (caused by: root)"
+func errorText(msg string, err error) string {
+	text := err.Error()
+	if msg != "" {
+		text = msg + ": " + text
+	}
+	chain := errorChain(err)
+	if len(chain) > 1 {
+		text += " (caused by: " + chain[len(chain)-1] + ")"
+	}
+	return text
+}
+
+// errorChain returns the messages of err and every error it wraps
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	return chain
+}
+
+// errorStack returns the program counters of the first error in the chain
+// with a StackTrace method returning a slice of uintptr based values
+func errorStack(err error) []uintptr {
+	for ; err != nil; err = errors.Unwrap(err) {
+		method := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		trace := method.Call(nil)[0]
+		if trace.Kind() != reflect.Slice || trace.Type().Elem().Kind() != reflect.Uintptr {
+			continue
+		}
+		pcs := make([]uintptr, trace.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(trace.Index(i).Uint())
+		}
+		return pcs
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Frame and stackErr mimic github.com/pkg/errors
+type Frame uintptr
+
+type stackErr struct {
+	error
+	pcs []Frame
+}
+
+func (e stackErr) StackTrace() []Frame { return e.pcs }
+
+func newStackErr(msg string) error {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(1, pcs)
+	frames := make([]Frame, n)
+	for i := range frames {
+		frames[i] = Frame(pcs[i])
+	}
+	return stackErr{errors.New(msg), frames}
+}
+
+func TestErr(t *testing.T) {
+	root := errors.New("connection refused")
+	deep := fmt.Errorf("load user: %w", fmt.Errorf("query: %w", fmt.Errorf("dial: %w", root)))
+	tests := []struct {
+		name string
+		err  error
+		msg  []any
+		want string
+	}{
+		{"nil", nil, []any{"ignored"}, ""},
+		{"plain", root, nil, "<E> ! connection refused\n"},
+		{"message", root, []any{"failed ", 2}, "<E> ! failed 2: connection refused\n"},
+		{"three levels", deep, []any{"request"}, "<E> ! request: load user: query: dial: connection refused (caused by: connection refused)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			lg.Err(tt.err, tt.msg...)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrJSON(t *testing.T) {
+	lg, buf := newTestLogger(t, WithJSON())
+	root := errors.New("connection refused")
+	lg.Err(fmt.Errorf("load user: %w", fmt.Errorf("query: %w", fmt.Errorf("dial: %w", root))), "request")
+
+	var entry struct {
+		Msg        string
+		Error      string
+		ErrorChain []string `json:"error_chain"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &entry); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"load user: query: dial: connection refused",
+		"query: dial: connection refused",
+		"dial: connection refused",
+		"connection refused",
+	}
+	if entry.Msg != "request" || entry.Error != want[0] || strings.Join(entry.ErrorChain, "|") != strings.Join(want, "|") {
+		t.Fatalf("entry = %+v", entry)
+	}
+}
+
+func TestErrStackTrace(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.Err(fmt.Errorf("wrapped: %w", newStackErr("boom")))
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) < 2 || !strings.Contains(lines[1], "newStackErr") {
+		t.Fatalf("frames of the error missing:\n%s", buf)
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Package wide exit hooks, run after the hooks of the logger
+var (
+	exitMu      sync.Mutex
+	exitHooks   []func()
+	exitOnce    sync.Once
+	exitTimeout atomic.Int64
+)
+
+func init() {
+	exitTimeout.Store(int64(5 * time.Second))
+}
+
+// RegisterExitHook adds fn to the functions run before any logger exits
+// on Fatal, for cleanup that deferred functions would miss
+func RegisterExitHook(fn func()) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitHooks = append(exitHooks, fn)
+}
+
+// SetExitHookTimeout bounds how long each exit hook may run before the
+// next one starts, 5s by default
+func SetExitHookTimeout(d time.Duration) {
+	exitTimeout.Store(int64(d))
+}
+
+// RegisterExitHook adds fn to the functions run when this logger exits on
+// Fatal, before the package wide ones
+func (lg *Logger) RegisterExitHook(fn func()) {
+	lg.exitMu.Lock()
+	defer lg.exitMu.Unlock()
+	lg.exitHooks = append(lg.exitHooks, fn)
+}
+
+// runExitHooks runs the hooks of the logger and then the package wide ones
+// in registration order, each set at most once. Concurrent Fatal calls wait
+// for the first one to finish.
+func (lg *core) runExitHooks() {
+	lg.exitOnce.Do(func() {
+		lg.exitMu.Lock()
+		hooks := lg.exitHooks
+		lg.exitMu.Unlock()
+		callExitHooks(hooks)
+	})
+	exitOnce.Do(func() {
+		exitMu.Lock()
+		hooks := exitHooks
+		exitMu.Unlock()
+		callExitHooks(hooks)
+	})
+}
+
+func callExitHooks(hooks []func()) {
+	timeout := time.Duration(exitTimeout.Load())
+	for _, fn := range hooks {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() { recover() }() // a failing hook must not stop the exit
+			fn()
+		}()
+
+		t := time.NewTimer(timeout)
+		select {
+		case <-done:
+		case <-t.C:
+		}
+		t.Stop()
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// keepExitHooks gives the test its own package wide exit hooks
+func keepExitHooks(t *testing.T) {
+	exitMu.Lock()
+	saved, timeout := exitHooks, exitTimeout.Load()
+	exitHooks = nil
+	exitOnce = sync.Once{}
+	exitMu.Unlock()
+	t.Cleanup(func() {
+		exitMu.Lock()
+		defer exitMu.Unlock()
+		exitHooks = saved
+		exitOnce = sync.Once{}
+		exitTimeout.Store(timeout)
+	})
+}
+
+func TestExitHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		hooks   func(lg *Logger, record func(string)) // registers the hooks
+		want    string
+	}{
+		{
+			name: "order",
+			hooks: func(lg *Logger, record func(string)) {
+				RegisterExitHook(func() { record("pkg1") })
+				lg.RegisterExitHook(func() { record("lg1") })
+				RegisterExitHook(func() { record("pkg2") })
+				lg.RegisterExitHook(func() { record("lg2") })
+			},
+			want: "lg1 lg2 pkg1 pkg2 exit",
+		},
+		{
+			name:    "hung hook",
+			timeout: 20 * time.Millisecond,
+			hooks: func(lg *Logger, record func(string)) {
+				lg.RegisterExitHook(func() { select {} })
+				lg.RegisterExitHook(func() { record("after") })
+			},
+			want: "after exit",
+		},
+		{
+			name: "panicking hook",
+			hooks: func(lg *Logger, record func(string)) {
+				RegisterExitHook(func() { panic("cleanup failed") })
+				RegisterExitHook(func() { record("after") })
+			},
+			want: "after exit",
+		},
+		{
+			name:  "no hooks",
+			hooks: func(lg *Logger, record func(string)) {},
+			want:  "exit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepExitHooks(t)
+			if tt.timeout > 0 {
+				SetExitHookTimeout(tt.timeout)
+			}
+			lg, buf := newTestLogger(t)
+
+			var mu sync.Mutex
+			var calls []string
+			record := func(s string) {
+				mu.Lock()
+				defer mu.Unlock()
+				if !strings.Contains(buf.String(), "<F>!!! boom") {
+					s += "(before the message)"
+				}
+				calls = append(calls, s)
+			}
+			tt.hooks(lg, record)
+			lg.SetExitFunc(func(int) { record("exit") })
+
+			start := time.Now()
+			lg.Fatal("boom")
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("exiting took %v", elapsed)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got := strings.Join(calls, " "); got != tt.want {
+				t.Fatalf("calls = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitHooksRunOnce(t *testing.T) {
+	keepExitHooks(t)
+	lg, _ := newTestLogger(t, WithSync(false))
+	var pkg, own, exits atomic.Int32
+	RegisterExitHook(func() {
+		pkg.Add(1)
+		time.Sleep(10 * time.Millisecond) // the other goroutines wait meanwhile
+	})
+	lg.RegisterExitHook(func() { own.Add(1) })
+	lg.SetExitFunc(func(int) {
+		if pkg.Load() != 1 {
+			t.Error("exited before the hooks finished")
+		}
+		exits.Add(1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.Fatal("boom")
+		}()
+	}
+	wg.Wait()
+	if pkg.Load() != 1 || own.Load() != 1 || exits.Load() != 8 {
+		t.Fatalf("package hook ran %d times, logger hook %d, exit %d", pkg.Load(), own.Load(), exits.Load())
+	}
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// FallbackOption configures Fallback
+type FallbackOption func(*fallbackWriter)
+
+// FallbackOnError calls fn with every error of the primary writer
+func FallbackOnError(fn func(err error)) FallbackOption {
+	return func(w *fallbackWriter) { w.onError = fn }
+}
+
+// FallbackQuarantine stops trying the primary writer after failures
+// consecutive errors, it is probed again once every probe interval. The
+// default is 3 failures and 10s.
+func FallbackQuarantine(failures int, probe time.Duration) FallbackOption {
+	return func(w *fallbackWriter) { w.maxFailures, w.probe = failures, probe }
+}
+
+// fallbackWriter writes to primary and to fallback when primary fails
+type fallbackWriter struct {
+	primary, fallback io.Writer
+	onError           func(err error)
+	maxFailures       int
+	probe             time.Duration
+	now               func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	retry    time.Time // primary is skipped until then
+}
+
+// Fallback returns a writer for New that writes to primary and, when that
+// fails, to fallback instead, typically os.Stderr
+func Fallback(primary, fallback io.Writer, opts ...FallbackOption) io.Writer {
+	w := &fallbackWriter{
+		primary:     primary,
+		fallback:    fallback,
+		maxFailures: 3,
+		probe:       10 * time.Second,
+		now:         time.Now,
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	return w
+}
+
+// WithFallback adds primary as a destination with fallback taking over
+// its messages while it fails, see Fallback
+func WithFallback(primary, fallback io.Writer, opts ...FallbackOption) Option {
+	return WithWriters(Fallback(primary, fallback, opts...))
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if w.retry.IsZero() || !now.Before(w.retry) {
+		n, err := w.primary.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err == nil {
+			w.failures, w.retry = 0, time.Time{}
+			return n, nil
+		}
+
+		if w.onError != nil {
+			w.onError(err)
+		}
+		w.failures++
+		if w.maxFailures > 0 && w.failures >= w.maxFailures {
+			w.retry = now.Add(w.probe)
+		}
+	}
+	return w.fallback.Write(p)
+}
+
+// Close closes the writers made by this package
+func (w *fallbackWriter) Close() error {
+	var err error
+	for _, x := range []io.Writer{w.primary, w.fallback} {
+		if mw, ok := x.(managedWriter); ok {
+			if cerr := mw.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
+
+func (w *fallbackWriter) managed() {}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingWriter fails every write while broken is set
+type failingWriter struct {
+	syncBuffer
+	broken   bool
+	attempts int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.attempts++
+	if w.broken {
+		return 0, errors.New("connection refused")
+	}
+	return w.syncBuffer.Write(p)
+}
+
+// shortWriter accepts one byte less than asked
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) { return len(p) - 1, nil }
+
+func TestFallback(t *testing.T) {
+	type step struct {
+		advance time.Duration
+		broken  bool
+		primary bool // the line ends up on the primary writer
+		tried   bool // the primary writer was tried
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"healthy", []step{
+			{0, false, true, true},
+			{0, false, true, true},
+		}},
+		{"failing writes go to the fallback", []step{
+			{0, true, false, true},
+			{0, false, true, true},
+		}},
+		{"quarantined after 2 failures", []step{
+			{0, true, false, true},
+			{0, true, false, true},
+			{0, false, false, false},
+			{time.Second, false, false, false},
+		}},
+		{"probed after the interval", []step{
+			{0, true, false, true},
+			{0, true, false, true},
+			{5 * time.Second, true, false, true}, // failed probe, quarantined again
+			{time.Second, false, false, false},
+			{5 * time.Second, false, true, true},
+			{0, false, true, true},
+		}},
+		{"successes reset the failures", []step{
+			{0, true, false, true},
+			{0, false, true, true},
+			{0, true, false, true},
+			{0, false, true, true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, fallback := &failingWriter{}, &syncBuffer{}
+			var errs []error
+			w := Fallback(primary, fallback, FallbackQuarantine(2, 5*time.Second),
+				FallbackOnError(func(err error) { errs = append(errs, err) })).(*fallbackWriter)
+			clock := &testClock{now: time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)}
+			w.now = clock.Now
+
+			failures := 0
+			for i, s := range tt.steps {
+				clock.Add(s.advance)
+				primary.broken = s.broken
+				attempts := primary.attempts
+				before, fallbackBefore := primary.String(), fallback.String()
+
+				if _, err := w.Write([]byte("line\n")); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				if tried := primary.attempts > attempts; tried != s.tried {
+					t.Fatalf("step %d: primary tried = %v, want %v", i, tried, s.tried)
+				}
+				gotPrimary := primary.String() != before
+				gotFallback := fallback.String() != fallbackBefore
+				if gotPrimary != s.primary || gotFallback == s.primary {
+					t.Fatalf("step %d: written to primary %v, fallback %v", i, gotPrimary, gotFallback)
+				}
+				if s.tried && s.broken {
+					failures++
+				}
+			}
+			if len(errs) != failures {
+				t.Fatalf("OnError called %d times, want %d", len(errs), failures)
+			}
+		})
+	}
+}
+
+func TestFallbackShortWrite(t *testing.T) {
+	fallback := &syncBuffer{}
+	var got error
+	w := Fallback(shortWriter{}, fallback, FallbackOnError(func(err error) { got = err }))
+	w.Write([]byte("line\n"))
+	if got == nil || fallback.String() != "line\n" {
+		t.Fatalf("error %v, fallback %q", got, fallback)
+	}
+}
+
+func TestWithFallback(t *testing.T) {
+	primary, fallback := &failingWriter{broken: true}, &syncBuffer{}
+	lg := NewLogger("TEST", WithFallback(primary, fallback), WithNoColor(), WithPrintTime(false))
+	lg.Info("one")
+	lg.Warn("two")
+	lg.Close()
+
+	if got, want := fallback.String(), "[TEST] [I]   one\n[TEST] [W] ? two\n"; got != want {
+		t.Fatalf("fallback got %q, want %q", got, want)
+	}
+	if strings.Contains(primary.String(), "one") {
+		t.Fatalf("the failing writer got %q", primary)
+	}
+}
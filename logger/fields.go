@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is a structured key-value pair attached to a log message
+type Field struct {
+	Key   string
+	Value any
+}
+
+// WithFields returns a derived logger that appends the fields to every
+// message. Map keys are sorted so the rendered order is stable; fields of
+// the parent come first and are overridden on key collision.
+func (lg *Logger) WithFields(fields map[string]any) *Logger {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	add := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		add = append(add, Field{Key: k, Value: fields[k]})
+	}
+	return lg.with(add)
+}
+
+// WithField returns a derived logger with a single field attached
+func (lg *Logger) WithField(key string, value any) *Logger {
+	return lg.with([]Field{{Key: key, Value: value}})
+}
+
+// with returns a copy of lg sharing the core with fields merged in
+func (lg *Logger) with(add []Field) *Logger {
+	child := lg.derive()
+	child.fields = mergeFields(lg.fields, add)
+	return child
+}
+
+// mergeFields returns a new slice, keys present in both keep their
+// position in base but take the value from add
+func mergeFields(base, add []Field) []Field {
+	out := make([]Field, len(base), len(base)+len(add))
+	copy(out, base)
+outer:
+	for _, f := range add {
+		for i := range out {
+			if out[i].Key == f.Key {
+				out[i].Value = f.Value
+				continue outer
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// renderFields formats fields as " key=value" pairs for text output
+func (lg *core) renderFields(fields []Field, color bool) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if color {
+		b.WriteString(string(Grey))
+	}
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(fieldValue(f.Value))
+	}
+	if color {
+		b.WriteString(string(Reset))
+	}
+	return b.String()
+}
+
+// fieldValue formats a value, quoting it when it would be ambiguous
+func fieldValue(v any) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " =\"\n\t") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Infow logs msg with fields given as alternating keys and values, like
+// lg.Infow("user created", "id", 42, "plan", "pro")
+func (lg *Logger) Infow(msg string, kv ...any) {
+	lg.logwDepth(1, LevelInfo, msg, kv...)
+}
+
+// Warnw is Infow at Warn
+func (lg *Logger) Warnw(msg string, kv ...any) {
+	lg.logwDepth(1, LevelWarn, msg, kv...)
+}
+
+// Errorw is Infow at Error
+func (lg *Logger) Errorw(msg string, kv ...any) {
+	lg.logwDepth(1, LevelError, msg, kv...)
+}
+
+// Debugw is Infow at Debug
+func (lg *Logger) Debugw(msg string, kv ...any) {
+	lg.logwDepth(1, LevelDebug, msg, kv...)
+}
+
+// Fatalw is Infow at Fatal, it exits afterwards
+func (lg *Logger) Fatalw(msg string, kv ...any) {
+	lg.logwDepth(1, LevelFatal, msg, kv...)
+}
+
+// logwDepth logs msg with the key-value pairs merged into the fields of lg.
+// Malformed pairs are kept under "!BADKEY" and reported at Error.
+func (lg *Logger) logwDepth(depth int, level LogLevel, msg string, kv ...any) {
+	ring := lg.ring.Load()
+	if ring == nil && !lg.enabled(level) {
+		return
+	}
+
+	fields, bad := kvFields(kv)
+	if bad != "" {
+		lg.logDepth(depth+1, LevelError, "logger: "+bad)
+	}
+	lg.with(fields).output(depth+1, level, msg)
+}
+
+// kvFields pairs up kv, bad describes the first malformed pair. A Field
+// stands for a whole pair.
+func kvFields(kv []any) (fields []Field, bad string) {
+	fields = make([]Field, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		if f, ok := kv[i].(Field); ok {
+			fields = append(fields, f)
+			i--
+			continue
+		}
+		if i+1 == len(kv) {
+			fields = append(fields, Field{Key: "!BADKEY", Value: kv[i]})
+			if bad == "" {
+				bad = fmt.Sprintf("odd number of key-value arguments, %v has no value", kv[i])
+			}
+			break
+		}
+		key, ok := kv[i].(string)
+		if !ok {
+			key = "!BADKEY"
+			if bad == "" {
+				bad = fmt.Sprintf("key %v is a %T, not a string", kv[i], kv[i])
+			}
+		}
+		fields = append(fields, Field{Key: key, Value: kv[i+1]})
+	}
+	return fields, bad
+}
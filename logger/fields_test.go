@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithFields(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{
+			"sorted keys",
+			func(lg *Logger) { lg.WithFields(map[string]any{"user_id": 7, "request_id": "r1"}).Info("hi") },
+			"[I]   hi request_id=r1 user_id=7\n",
+		},
+		{
+			"parent first",
+			func(lg *Logger) { lg.WithField("b", 1).WithField("a", 2).Info("hi") },
+			"[I]   hi b=1 a=2\n",
+		},
+		{
+			"override keeps position",
+			func(lg *Logger) { lg.WithField("a", 1).WithField("b", 2).WithField("a", 3).Info("hi") },
+			"[I]   hi a=3 b=2\n",
+		},
+		{
+			"quoted values",
+			func(lg *Logger) { lg.WithFields(map[string]any{"q": "a b", "e": ""}).Info("hi") },
+			"[I]   hi e=\"\" q=\"a b\"\n",
+		},
+		{
+			"parent unchanged",
+			func(lg *Logger) { lg.WithField("a", 1); lg.Info("hi") },
+			"[I]   hi\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFieldsColor(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.WithField("k", "v").Info("hi")
+	if want := string(Grey) + " k=v" + string(Reset); !strings.Contains(buf.String(), want) {
+		t.Fatalf("fields aren't grey: %q", buf.String())
+	}
+}
+
+func TestWithFieldsSharesParent(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	child := lg.WithField("k", 1)
+	for i := 0; i < 100; i++ {
+		child.Info("child")
+	}
+	lg.Close()
+
+	if got := strings.Count(buf.String(), "child k=1\n"); got != 100 {
+		t.Fatalf("Close of the parent flushed %d of 100 child lines", got)
+	}
+}
+
+func TestKeyValueMethods(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{"Infow", func(lg *Logger) { lg.Infow("user created", "id", 42, "plan", "pro") }, "[I]   user created id=42 plan=pro\n"},
+		{"Warnw", func(lg *Logger) { lg.Warnw("slow", "ms", 250) }, "[W] ? slow ms=250\n"},
+		{"Errorw", func(lg *Logger) { lg.Errorw("failed", "err", "a b") }, `<E> ! failed err="a b"` + "\n"},
+		{"Debugw", func(lg *Logger) { lg.Debugw("cache", "hit", true) }, "[D]   cache hit=true\n"},
+		{"no pairs", func(lg *Logger) { lg.Infow("plain") }, "[I]   plain\n"},
+		{"merged", func(lg *Logger) { lg.WithField("req", 7).Infow("done", "status", 200) }, "[I]   done req=7 status=200\n"},
+		{"call wins", func(lg *Logger) { lg.WithField("user", "ann").Infow("done", "user", "bob") }, "[I]   done user=bob\n"},
+		{"typed field", func(lg *Logger) { lg.Infow("done", Int("n", 3), "ok", true) }, "[I]   done n=3 ok=true\n"},
+		{"odd count", func(lg *Logger) { lg.Infow("done", "id", 42, "plan") },
+			"<E> ! logger: odd number of key-value arguments, plan has no value\n[I]   done id=42 !BADKEY=plan\n"},
+		{"non-string key", func(lg *Logger) { lg.Infow("done", 42, "x", "ok", 1) },
+			"<E> ! logger: key 42 is a int, not a string\n[I]   done !BADKEY=x ok=1\n"},
+		{"disabled", func(lg *Logger) { lg.AtLevel(LevelInfo).Debugw("done", "id") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelDebug))
+			lg.SetPrintModule(false)
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyValueFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{"json", FormatJSON, `"msg":"created","tag":"x","id":42,"plan":"pro"}`},
+		{"logfmt", FormatLogfmt, `msg=created id=42 plan=pro tag=x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithFormat(tt.format))
+			lg.WithField("tag", "x").Infow("created", "id", 42, "plan", "pro")
+			if got := buf.String(); !strings.HasSuffix(got, tt.want+"\n") {
+				t.Fatalf("got %s, want it to end in %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	at := time.Date(2026, 10, 14, 9, 5, 7, 120e6, time.UTC)
+	tests := []struct {
+		name      string
+		field     Field
+		text      string
+		json      string
+		logfmt    string
+		hookValue string
+	}{
+		{"dur", Dur("d", 1500*time.Millisecond), `d=1.50s`, `"d":1500`, `d=1500`, "1.5s"},
+		{"dur minutes", Dur("d", 83456789*time.Microsecond), `d=1m23s`, `"d":83456.789`, `d=83456.789`, "1m23.456789s"},
+		{"time", Time("t", at), `t="2026-10-14 09:05:07.120"`, `"t":"2026-10-14T09:05:07.12Z"`, `t=2026-10-14T09:05:07.12Z`, "2026-10-14 09:05:07.12 +0000 UTC"},
+		{"err", Err("err", errors.New(`no "route"`)), `err="no \"route\""`, `"err":"no \"route\""`, `err="no \"route\""`, `no "route"`},
+		{"nil err", Err("err", nil), `err=<nil>`, `"err":null`, `err=<nil>`, "<nil>"},
+		{"int", Int("n", -3), `n=-3`, `"n":-3`, `n=-3`, "-3"},
+		{"string", String("s", "a b"), `s="a b"`, `"s":"a b"`, `s="a b"`, "a b"},
+		{"bool", Bool("b", true), `b=true`, `"b":true`, `b=true`, "true"},
+		{"any duration", Any("a", 2*time.Second), `a=2.00s`, `"a":2000`, `a=2000`, "2s"},
+		{"any time", Any("a", at), `a="2026-10-14 09:05:07.120"`, `"a":"2026-10-14T09:05:07.12Z"`, `a=2026-10-14T09:05:07.12Z`, "2026-10-14 09:05:07.12 +0000 UTC"},
+		{"any error", Any("a", errors.New("x")), `a=x`, `"a":"x"`, `a=x`, "x"},
+		{"any slice", Any("a", []int{1, 2}), `a="[1 2]"`, `"a":[1,2]`, `a="[1 2]"`, "[1 2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, f := range []struct {
+				format Format
+				want   string
+			}{
+				{FormatText, " " + tt.text + "\n"},
+				{FormatJSON, `"msg":"m",` + tt.json + "}\n"},
+				{FormatLogfmt, " msg=m " + tt.logfmt + "\n"},
+			} {
+				lg, buf := newTestLogger(t, WithFormat(f.format))
+				lg.With(tt.field).Info("m")
+				if got := buf.String(); !strings.HasSuffix(got, f.want) {
+					t.Errorf("format %d: got %s, want it to end in %s", f.format, got, f.want)
+				}
+			}
+
+			lg, _ := newTestLogger(t)
+			var got any
+			lg.AddHook(func(_ LogLevel, _, _ string, fields map[string]any) { got = fields[tt.field.Key] })
+			lg.With(tt.field).Info("m")
+			if s := fmt.Sprint(got); s != tt.hookValue {
+				t.Errorf("hook got %s, want %s", s, tt.hookValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package logger
+
+import "time"
+
+// typedValue is a field value rendered for people in text output and for
+// machines in JSON and logfmt
+type typedValue interface {
+	String() string
+	machine() any
+	value() any // what hooks receive
+}
+
+type durValue time.Duration
+
+func (d durValue) String() string { return Duration(time.Duration(d)) }
+func (d durValue) machine() any   { return float64(d) / float64(time.Millisecond) }
+func (d durValue) value() any     { return time.Duration(d) }
+
+type timeValue time.Time
+
+func (t timeValue) String() string { return time.Time(t).Format("2006-01-02 15:04:05.000") }
+func (t timeValue) machine() any   { return time.Time(t).Format(time.RFC3339Nano) }
+func (t timeValue) value() any     { return time.Time(t) }
+
+type errValue struct{ err error }
+
+func (e errValue) String() string {
+	if e.err == nil {
+		return "<nil>"
+	}
+	return e.err.Error()
+}
+
+func (e errValue) machine() any {
+	if e.err == nil {
+		return nil
+	}
+	return e.err.Error()
+}
+
+func (e errValue) value() any { return e.err }
+
+// Dur is a duration field, humanized like "1m23s" in text output and in
+// milliseconds in JSON and logfmt
+func Dur(key string, d time.Duration) Field {
+	return Field{Key: key, Value: durValue(d)}
+}
+
+// Time is a time field, RFC 3339 in JSON and logfmt
+func Time(key string, t time.Time) Field {
+	return Field{Key: key, Value: timeValue(t)}
+}
+
+// Err is an error field holding the message of err
+func Err(key string, err error) Field {
+	return Field{Key: key, Value: errValue{err}}
+}
+
+// Int is an integer field
+func Int(key string, v int) Field {
+	return Field{Key: key, Value: v}
+}
+
+// String is a string field
+func String(key string, v string) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Bool is a boolean field
+func Bool(key string, v bool) Field {
+	return Field{Key: key, Value: v}
+}
+
+// Any is a field of any value, durations, times and errors get the typed
+// rendering of Dur, Time and Err
+func Any(key string, v any) Field {
+	switch t := v.(type) {
+	case time.Duration:
+		return Dur(key, t)
+	case time.Time:
+		return Time(key, t)
+	case error:
+		return Err(key, t)
+	}
+	return Field{Key: key, Value: v}
+}
+
+// With returns a derived logger with the fields attached in order
+func (lg *Logger) With(fields ...Field) *Logger {
+	return lg.with(fields)
+}
+
+// machineValue returns the value of v for JSON and logfmt
+func machineValue(v any) any {
+	if tv, ok := v.(typedValue); ok {
+		return tv.machine()
+	}
+	return v
+}
+
+// hookValue returns the value of v handed to hooks
+func hookValue(v any) any {
+	if tv, ok := v.(typedValue); ok {
+		return tv.value()
+	}
+	return v
+}
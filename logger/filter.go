@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"regexp"
+	"slices"
+)
+
+// FilterAction decides what happens to a message matching a FilterRule
+type FilterAction int
+
+const (
+	FilterAllow FilterAction = iota
+	FilterDrop
+)
+
+// FilterRule matches messages whose text matches Pattern and whose level
+// is within MinLevel and MaxLevel. When both bounds are zero the rule
+// applies to every level, a MaxLevel below MinLevel leaves the upper bound
+// open so MinLevel alone means "MinLevel and above".
+type FilterRule struct {
+	Pattern  *regexp.Regexp
+	Action   FilterAction
+	MinLevel LogLevel
+	MaxLevel LogLevel
+}
+
+// FilterID identifies a rule for RemoveFilter
+type FilterID int
+
+type filterEntry struct {
+	id   FilterID
+	rule FilterRule
+}
+
+// filterSet is replaced as a whole, nil means no filtering
+type filterSet struct {
+	rules []filterEntry
+	def   FilterAction
+}
+
+func (r FilterRule) matches(level LogLevel, msg string) bool {
+	if r.MinLevel != 0 || r.MaxLevel != 0 {
+		if level < r.MinLevel || r.MaxLevel >= r.MinLevel && level > r.MaxLevel {
+			return false
+		}
+	}
+	return r.Pattern != nil && r.Pattern.MatchString(msg)
+}
+
+// AddFilter appends a rule shared by lg and its derived loggers. Rules are
+// checked in order on the consumer goroutine and the first match decides,
+// messages matching none get the action set by SetFilterDefault. Filters
+// only see messages that already passed the level filter.
+func (lg *Logger) AddFilter(rule FilterRule) FilterID {
+	var id FilterID
+	lg.updateFilters(func(s *filterSet) {
+		lg.nextFilter++
+		id = lg.nextFilter
+		s.rules = append(s.rules, filterEntry{id: id, rule: rule})
+	})
+	return id
+}
+
+// RemoveFilter removes a rule, unknown ids are ignored
+func (lg *Logger) RemoveFilter(id FilterID) {
+	lg.updateFilters(func(s *filterSet) {
+		s.rules = slices.DeleteFunc(s.rules, func(e filterEntry) bool { return e.id == id })
+	})
+}
+
+// Filters returns the rules in the order they are checked
+func (lg *Logger) Filters() []FilterRule {
+	s := lg.filters.Load()
+	if s == nil {
+		return nil
+	}
+	rules := make([]FilterRule, len(s.rules))
+	for i, e := range s.rules {
+		rules[i] = e.rule
+	}
+	return rules
+}
+
+// SetFilterDefault sets the action for messages matching no rule,
+// FilterAllow by default. FilterDrop turns the rules into an allow list.
+func (lg *Logger) SetFilterDefault(action FilterAction) {
+	lg.updateFilters(func(s *filterSet) { s.def = action })
+}
+
+// updateFilters applies fn to a copy of the filters and publishes it in
+// order with the queued messages
+func (lg *core) updateFilters(fn func(s *filterSet)) {
+	lg.filtersMu.Lock()
+	defer lg.filtersMu.Unlock()
+
+	s := &filterSet{}
+	if old := lg.filters.Load(); old != nil {
+		s.rules = slices.Clone(old.rules)
+		s.def = old.def
+	}
+	fn(s)
+	if len(s.rules) == 0 && s.def == FilterAllow {
+		s = nil
+	}
+	lg.control(func() { lg.filters.Store(s) })
+}
+
+// filtered reports whether m is dropped by the filters, Fatal and Panic
+// never are
+func (lg *core) filtered(m logMessage) bool {
+	s := lg.filters.Load()
+	if s == nil || m.level >= LevelFatal {
+		return false
+	}
+	for _, e := range s.rules {
+		if e.rule.matches(m.level, m.msg) {
+			return e.rule.Action == FilterDrop
+		}
+	}
+	return s.def == FilterDrop
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFilters(t *testing.T) {
+	health := regexp.MustCompile(`healthcheck OK`)
+	payments := regexp.MustCompile(`^payment`)
+	tests := []struct {
+		name  string
+		setup func(lg *Logger)
+		want  string // first letters of the logged messages kept
+	}{
+		{"no filters", func(lg *Logger) {}, "hpdwe"},
+		{"drop", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Pattern: health, Action: FilterDrop})
+		}, "pdwe"},
+		{"allow list", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Pattern: payments, Action: FilterAllow})
+			lg.SetFilterDefault(FilterDrop)
+		}, "p"},
+		{"first match wins", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Pattern: regexp.MustCompile(`disk`), Action: FilterAllow})
+			lg.AddFilter(FilterRule{Pattern: regexp.MustCompile(`.`), Action: FilterDrop})
+		}, "d"},
+		{"level bounds", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Pattern: regexp.MustCompile(`.`), Action: FilterDrop, MinLevel: LevelDebug, MaxLevel: LevelInfo})
+		}, "we"},
+		{"min level only", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Pattern: regexp.MustCompile(`.`), Action: FilterDrop, MinLevel: LevelWarn})
+		}, "hpd"},
+		{"nil pattern", func(lg *Logger) {
+			lg.AddFilter(FilterRule{Action: FilterDrop})
+		}, "hpdwe"},
+		{"removed", func(lg *Logger) {
+			id := lg.AddFilter(FilterRule{Pattern: health, Action: FilterDrop})
+			lg.SetFilterDefault(FilterDrop)
+			lg.RemoveFilter(id)
+			lg.SetFilterDefault(FilterAllow)
+			lg.RemoveFilter(id)
+		}, "hpdwe"},
+		{"level filter first", func(lg *Logger) {
+			lg.SetLevel(LevelWarn)
+			lg.AddFilter(FilterRule{Pattern: regexp.MustCompile(`.`), Action: FilterAllow})
+		}, "we"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelDebug))
+			lg.SetPrintModule(false)
+			tt.setup(lg)
+
+			lg.Info("healthcheck OK")
+			lg.Info("payment received")
+			lg.Debug("disk at 40%")
+			lg.Warn("warn: slow")
+			lg.Error("error: failed")
+
+			var got strings.Builder
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				if line != "" {
+					got.WriteByte(line[6])
+				}
+			}
+			if got.String() != tt.want {
+				t.Fatalf("kept %q, want %q:\n%s", got.String(), tt.want, buf)
+			}
+		})
+	}
+}
+
+func TestFiltersList(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	if lg.Filters() != nil {
+		t.Fatal("new logger has filters")
+	}
+	a := lg.AddFilter(FilterRule{Pattern: regexp.MustCompile("a"), Action: FilterDrop})
+	lg.AddFilter(FilterRule{Pattern: regexp.MustCompile("b")})
+	lg.Sub("S", "").AddFilter(FilterRule{Pattern: regexp.MustCompile("c")})
+	lg.RemoveFilter(a)
+
+	var patterns []string
+	for _, r := range lg.Filters() {
+		patterns = append(patterns, r.Pattern.String())
+	}
+	if strings.Join(patterns, " ") != "b c" {
+		t.Fatalf("filters = %v, want b c shared with the sub logger", patterns)
+	}
+}
+
+func TestFiltersKeepFatal(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetExitFunc(func(int) {})
+	lg.SetFilterDefault(FilterDrop)
+	lg.Info("dropped")
+	lg.Fatal("fatal")
+	if buf.String() != "[TEST] <F>!!! fatal\n" {
+		t.Fatalf("output = %q", buf)
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"log"
+	"strings"
+)
+
+// Std returns a *log.Logger writing through lg at LevelPrint, sharing its
+// writers, colors and rendering. Use StdLogger for a different level.
+func (lg *Logger) Std() *log.Logger {
+	return lg.StdLogger(LevelPrint)
+}
+
+// SetFlags maps the standard log flags onto the logger in one step, the
+// module prefix is unaffected. Ldate, Ltime and Lmicroseconds pick the
+// timestamp layout, LUTC renders it in UTC, Lshortfile and Llongfile turn
+// on caller reporting and Lmsgprefix moves the SetPrefix text in front of
+// the message.
+func (lg *Logger) SetFlags(flag int) {
+	var layout []string
+	if flag&log.Ldate != 0 {
+		layout = append(layout, "2006/01/02")
+	}
+	if flag&(log.Ltime|log.Lmicroseconds) != 0 {
+		t := "15:04:05"
+		if flag&log.Lmicroseconds != 0 {
+			t += ".000000"
+		}
+		layout = append(layout, t)
+	}
+
+	lg.updateOpts(func(o *renderOptions) {
+		o.printTime = len(layout) > 0
+		if o.printTime {
+			o.timeFormat = strings.Join(layout, " ")
+		}
+		o.utc = flag&log.LUTC != 0
+		o.msgPrefix = flag&log.Lmsgprefix != 0
+	})
+	lg.reportCaller.Store(flag&(log.Lshortfile|log.Llongfile) != 0)
+}
+
+// Flags reports the current settings as standard log flags, a custom time
+// layout maps to the closest of Ldate and Ltime
+func (lg *Logger) Flags() int {
+	o := lg.opts.Load()
+	flag := 0
+	if o.printTime {
+		if strings.Contains(o.timeFormat, "2006") {
+			flag |= log.Ldate
+		}
+		if strings.Contains(o.timeFormat, "15:04:05.000000") {
+			flag |= log.Lmicroseconds
+		} else if strings.Contains(o.timeFormat, "15:04") {
+			flag |= log.Ltime
+		}
+		if o.utc {
+			flag |= log.LUTC
+		}
+	}
+	if o.msgPrefix {
+		flag |= log.Lmsgprefix
+	}
+	if lg.reportCaller.Load() {
+		flag |= log.Lshortfile
+	}
+	return flag
+}
+
+// SetPrefix writes prefix at the start of every text line, in front of
+// the module. With Lmsgprefix it goes in front of the message instead.
+func (lg *Logger) SetPrefix(prefix string) {
+	lg.updateOpts(func(o *renderOptions) { o.prefix = prefix })
+}
+
+// Prefix returns the text set by SetPrefix
+func (lg *Logger) Prefix() string {
+	return lg.opts.Load().prefix
+}
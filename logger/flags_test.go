@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   int
+		prefix string
+		flags  int // reported by Flags
+		want   string
+	}{
+		{"none", 0, "", 0, "[TEST] [I]   hi"},
+		{"std", log.LstdFlags, "", log.LstdFlags, "[TEST] 2026/10/14 09:05:07 [I]   hi"},
+		{"time only", log.Ltime, "", log.Ltime, "[TEST] 09:05:07 [I]   hi"},
+		{"microseconds", log.Ldate | log.Lmicroseconds, "", log.Ldate | log.Lmicroseconds, "[TEST] 2026/10/14 09:05:07.123456 [I]   hi"},
+		{"utc", log.Ltime | log.LUTC, "", log.Ltime | log.LUTC, "[TEST] 08:05:07 [I]   hi"},
+		{"prefix", 0, "web: ", 0, "web: [TEST] [I]   hi"},
+		{"message prefix", log.Lmsgprefix, "web: ", log.Lmsgprefix, "[TEST] [I]   web: hi"},
+		{"utc without time", log.LUTC, "", 0, "[TEST] [I]   hi"},
+	}
+	now := time.Date(2026, 10, 14, 9, 5, 7, 123456789, time.FixedZone("CET", 3600))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetClock(func() time.Time { return now })
+			lg.SetFlags(tt.flag)
+			lg.SetPrefix(tt.prefix)
+
+			if got := lg.Flags(); got != tt.flags {
+				t.Errorf("Flags = %#x, want %#x", got, tt.flags)
+			}
+			if lg.Prefix() != tt.prefix {
+				t.Errorf("Prefix = %q", lg.Prefix())
+			}
+			lg.Info("hi")
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFlagsCaller(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetFlags(log.Lshortfile)
+	if lg.Flags() != log.Lshortfile {
+		t.Fatalf("Flags = %#x", lg.Flags())
+	}
+	lg.Info("hi")
+	if !strings.Contains(buf.String(), "flags_test.go:") {
+		t.Fatalf("no caller in %q", buf)
+	}
+}
+
+func TestStd(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	std := lg.Std()
+	std.Printf("listening on %s", ":8080")
+	std.Println("second")
+	if got, want := buf.String(), "[TEST] listening on :8080\n[TEST] second\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestSetFlagsConcurrent(t *testing.T) {
+	lg, buf := newTestLogger(t, WithSync(false))
+	std := lg.Std()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			lg.SetFlags([]int{0, log.LstdFlags, log.Lmicroseconds | log.Lmsgprefix}[i%3])
+			lg.SetPrefix([]string{"", "a: "}[i%2])
+			_ = lg.Flags()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			lg.Info("x")
+			std.Print("y")
+		}
+	}()
+	wg.Wait()
+	lg.Flush()
+	if got := strings.Count(buf.String(), "\n"); got != 400 {
+		t.Fatalf("wrote %d lines, want 400", got)
+	}
+}
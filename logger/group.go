@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Group is a logger whose messages are indented below a "▶ title" header.
+// Close prints a summary that fails when an error was logged inside.
+type Group struct {
+	*Logger
+	title  string
+	start  time.Time
+	parent *Logger
+	level  int
+	failed atomic.Bool
+	closed atomic.Bool
+}
+
+// Group opens a group, messages logged through it are indented by two
+// spaces per nesting level after the prefix and the level tag
+func (lg *Logger) Group(title string) *Group {
+	g := &Group{title: title, start: lg.now(), parent: lg, level: lg.group.depth() + 1}
+	g.Logger = lg.derive()
+	g.Logger.group = g
+
+	lg.logDepth(1, LevelInfo, "▶ "+title)
+	return g
+}
+
+// Close prints "✔ title (1.2s)", or "✘ title" at Error when an error was
+// logged inside the group or its nested groups. It does not close the
+// underlying logger.
+func (g *Group) Close() {
+	if g.closed.Swap(true) {
+		return
+	}
+	if g.failed.Load() {
+		g.parent.logDepth(1, LevelError, "✘ "+g.title)
+		return
+	}
+	g.parent.logDepth(1, LevelInfo, "✔ "+g.title+" ("+humanDuration(g.parent.now().Sub(g.start))+")")
+}
+
+// Failed reports whether an error was logged inside the group
+func (g *Group) Failed() bool {
+	return g.failed.Load()
+}
+
+func (g *Group) depth() int {
+	if g == nil {
+		return 0
+	}
+	return g.level
+}
+
+// fail marks g and the groups it is nested in as failed
+func (g *Group) fail() {
+	for ; g != nil; g = g.parent.group {
+		g.failed.Store(true)
+	}
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, lg *Logger, now *time.Time)
+		want []string
+	}{
+		{
+			name: "two levels",
+			run: func(t *testing.T, lg *Logger, now *time.Time) {
+				deploy := lg.Group("deploy")
+				deploy.Info("pull")
+				build := deploy.Group("build")
+				build.Warn("cached layer")
+				*now = now.Add(1200 * time.Millisecond)
+				build.Close()
+				deploy.Info("restart")
+				deploy.Close()
+				deploy.Close()
+				lg.Info("after")
+			},
+			want: []string{
+				"[I]   ▶ deploy",
+				"[I]     pull",
+				"[I]     ▶ build",
+				"[W] ?     cached layer",
+				"[I]     ✔ build (1.20s)",
+				"[I]     restart",
+				"[I]   ✔ deploy (1.20s)",
+				"[I]   after",
+			},
+		},
+		{
+			name: "failure in nested group",
+			run: func(t *testing.T, lg *Logger, now *time.Time) {
+				deploy := lg.Group("deploy")
+				migrate := deploy.Group("migrate")
+				migrate.Error("table locked")
+				migrate.Close()
+				deploy.Close()
+				if !deploy.Failed() || !migrate.Failed() {
+					t.Error("groups not marked as failed")
+				}
+			},
+			want: []string{
+				"[I]   ▶ deploy",
+				"[I]     ▶ migrate",
+				"<E> !     table locked",
+				"<E> !   ✘ migrate",
+				"<E> ! ✘ deploy",
+			},
+		},
+		{
+			name: "failure stays inside sibling",
+			run: func(t *testing.T, lg *Logger, now *time.Time) {
+				deploy := lg.Group("deploy")
+				deploy.Group("pull").Close()
+				build := deploy.Group("build")
+				build.Error("oom")
+				build.Close()
+				lg.Group("cleanup").Close()
+			},
+			want: []string{
+				"[I]   ▶ deploy",
+				"[I]     ▶ pull",
+				"[I]     ✔ pull (0ns)",
+				"[I]     ▶ build",
+				"<E> !     oom",
+				"<E> !   ✘ build",
+				"[I]   ▶ cleanup",
+				"[I]   ✔ cleanup (0ns)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			lg.SetClock(func() time.Time { return now })
+
+			tt.run(t, lg, &now)
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestGroupIndentAfterTimestamp(t *testing.T) {
+	lg, buf := newTestLogger(t, WithPrintTime(true))
+	lg.SetClock(func() time.Time { return time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local) })
+
+	g := lg.Group("deploy")
+	g.Info("pull")
+	g.Close()
+
+	want := "[TEST] 2026/10/14 09:05:07 [I]     pull\n"
+	if got := strings.Split(buf.String(), "\n")[1] + "\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
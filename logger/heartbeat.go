@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeartbeatOption configures Logger.StartHeartbeat
+type HeartbeatOption func(*heartbeatConfig)
+
+type heartbeatConfig struct {
+	always bool
+	tick   <-chan time.Time
+}
+
+// HeartbeatAlways beats even when other messages were logged during the
+// interval
+func HeartbeatAlways() HeartbeatOption {
+	return func(c *heartbeatConfig) { c.always = true }
+}
+
+// HeartbeatTicker drives the heartbeat from tick instead of a ticker with
+// the interval, meant for tests
+func HeartbeatTicker(tick <-chan time.Time) HeartbeatOption {
+	return func(c *heartbeatConfig) { c.tick = tick }
+}
+
+// StartHeartbeat logs an Info line every interval with the uptime, the
+// messages logged since the previous beat by level, the queue length and
+// the dropped count, followed by the result of extra when it isn't nil.
+// A beat is skipped when something else was logged during the interval.
+// The returned function stops the heartbeat and may be called repeatedly.
+func (lg *Logger) StartHeartbeat(interval time.Duration, extra func() string, opts ...HeartbeatOption) func() {
+	c := &heartbeatConfig{}
+	for _, o := range opts {
+		o(c)
+	}
+	tick := c.tick
+	var ticker *time.Ticker
+	if tick == nil {
+		ticker = time.NewTicker(interval)
+		tick = ticker.C
+	}
+
+	var (
+		mu     sync.Mutex
+		counts = map[LogLevel]int{}
+		active bool // something was logged since the last tick
+	)
+	hook := lg.AddHook(func(level LogLevel, _ string, _ string, _ map[string]any) {
+		mu.Lock()
+		counts[level]++
+		active = true
+		mu.Unlock()
+	})
+
+	start := lg.now()
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-tick:
+			case <-quit:
+				return
+			}
+
+			mu.Lock()
+			if active && !c.always {
+				active = false
+				mu.Unlock()
+				continue
+			}
+			total := 0
+			for _, n := range counts {
+				total += n
+			}
+			var b strings.Builder
+			noun := "messages"
+			if total == 1 {
+				noun = "message"
+			}
+			fmt.Fprintf(&b, "heartbeat: up %s, %d %s", Duration(lg.now().Sub(start)), total, noun)
+			if total > 0 {
+				var parts []string
+				for level := LevelTrace; level <= LevelPanic; level++ {
+					if n := counts[level]; n > 0 {
+						parts = append(parts, fmt.Sprintf("%s %d", level, n))
+					}
+				}
+				b.WriteString(" (" + strings.Join(parts, ", ") + ")")
+			}
+			clear(counts)
+			active = false
+			mu.Unlock()
+
+			fmt.Fprintf(&b, ", queue %d, dropped %d", lg.QueueLen(), lg.Dropped())
+			if extra != nil {
+				if s := extra(); s != "" {
+					b.WriteString(", " + s)
+				}
+			}
+			if lg.enabled(LevelInfo) {
+				m := lg.message(LevelInfo, b.String())
+				m.internal = true // not counted by the next beat
+				lg.emit(m)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+			if ticker != nil {
+				ticker.Stop()
+			}
+			lg.RemoveHook(hook)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []HeartbeatOption
+		extra func() string
+		level LogLevel
+		log   func(lg *Logger)
+		beats int
+		want  []string
+	}{
+		{
+			name:  "always",
+			opts:  []HeartbeatOption{HeartbeatAlways()},
+			log:   func(lg *Logger) { lg.Info("a"); lg.Info("b"); lg.Warn("c") },
+			beats: 2,
+			want: []string{
+				"[I]   a", "[I]   b", "[W] ? c",
+				"[I]   heartbeat: up 1m, 3 messages (info 2, warn 1), queue 0, dropped 0",
+				"[I]   heartbeat: up 2m, 0 messages, queue 0, dropped 0",
+			},
+		},
+		{
+			name:  "skipped after activity",
+			log:   func(lg *Logger) { lg.Error("x") },
+			beats: 2,
+			want: []string{
+				"<E> ! x",
+				"[I]   heartbeat: up 2m, 1 message (error 1), queue 0, dropped 0",
+			},
+		},
+		{
+			name:  "extra",
+			extra: func() string { return "rows 42" },
+			log:   func(lg *Logger) {},
+			beats: 1,
+			want:  []string{"[I]   heartbeat: up 1m, 0 messages, queue 0, dropped 0, rows 42"},
+		},
+		{
+			name:  "empty extra",
+			extra: func() string { return "" },
+			log:   func(lg *Logger) {},
+			beats: 1,
+			want:  []string{"[I]   heartbeat: up 1m, 0 messages, queue 0, dropped 0"},
+		},
+		{
+			name:  "below level",
+			level: LevelWarn,
+			log:   func(lg *Logger) {},
+			beats: 2,
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(tt.level))
+			lg.SetPrintModule(false)
+			clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			lg.SetClock(clock.Now)
+
+			tick := make(chan time.Time)
+			stop := lg.StartHeartbeat(time.Minute, tt.extra, append(tt.opts, HeartbeatTicker(tick))...)
+			tt.log(lg)
+			for i := 0; i < tt.beats; i++ {
+				clock.Add(time.Minute)
+				tick <- clock.Now() // received once the previous beat is done
+			}
+			stop()
+			stop()
+
+			var got []string
+			if out := strings.TrimSuffix(buf.String(), "\n"); out != "" {
+				got = strings.Split(out, "\n")
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestHeartbeatStopRemovesHook(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	hooks := func() int {
+		lg.hooksMu.RLock()
+		defer lg.hooksMu.RUnlock()
+		return len(lg.hooks)
+	}
+	before := hooks()
+	stop := lg.StartHeartbeat(time.Hour, nil)
+	if hooks() != before+1 {
+		t.Fatal("no hook counting messages")
+	}
+	stop()
+	if hooks() != before {
+		t.Fatal("Stop left the hook behind")
+	}
+}
@@ -0,0 +1,303 @@
+package logger
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// keywordSet is an immutable set of highlighted words. Exact words match
+// case-sensitively, folded words are stored upper-cased and match any case.
+type keywordSet struct {
+	exact    map[string]Color
+	folded   map[string]Color
+	patterns []pattern
+}
+
+// pattern is a regex highlight rule
+type pattern struct {
+	re    *regexp.Regexp
+	color Color
+}
+
+// Package wide keywords, used by loggers without their own set
+var (
+	keywords   atomic.Pointer[keywordSet]
+	keywordsMu sync.Mutex // serializes updates of keyword sets
+)
+
+func init() {
+	set := newKeywordSet(highlights)
+	set.patterns = humanizePatterns()
+	keywords.Store(set)
+}
+
+func newKeywordSet(words map[string]Color) *keywordSet {
+	set := &keywordSet{exact: map[string]Color{}, folded: map[string]Color{}}
+	for w, c := range words {
+		set.exact[w] = c
+	}
+	return set
+}
+
+// updateKeywords applies fn to a copy of the set in p and publishes it, an
+// empty p starts from the package set
+func updateKeywords(p *atomic.Pointer[keywordSet], fn func(set *keywordSet)) {
+	keywordsMu.Lock()
+	defer keywordsMu.Unlock()
+
+	cur := p.Load()
+	if cur == nil {
+		cur = keywords.Load()
+	}
+	set := cur.clone()
+	fn(set)
+	p.Store(set)
+}
+
+// clone returns a copy that can be modified before it is published
+func (k *keywordSet) clone() *keywordSet {
+	n := &keywordSet{
+		exact:  make(map[string]Color, len(k.exact)),
+		folded: make(map[string]Color, len(k.folded)),
+	}
+	for w, c := range k.exact {
+		n.exact[w] = c
+	}
+	for w, c := range k.folded {
+		n.folded[w] = c
+	}
+	n.patterns = append([]pattern(nil), k.patterns...)
+	return n
+}
+
+func (k *keywordSet) lookup(word string) (Color, bool) {
+	if c, ok := k.exact[word]; ok {
+		return c, true
+	}
+	if len(k.folded) > 0 {
+		c, ok := k.folded[strings.ToUpper(word)]
+		return c, ok
+	}
+	return "", false
+}
+
+// AddHighlight colors word wherever it appears as a whole word. Keywords
+// are made of letters, digits and underscores and match case-sensitively.
+func AddHighlight(word string, color Color) {
+	updateKeywords(&keywords, func(set *keywordSet) { set.exact[word] = color })
+}
+
+// AddHighlightFold is AddHighlight matching word in any case
+func AddHighlightFold(word string, color Color) {
+	updateKeywords(&keywords, func(set *keywordSet) { set.folded[strings.ToUpper(word)] = color })
+}
+
+// RemoveHighlight stops highlighting word, including the defaults
+func RemoveHighlight(word string) {
+	updateKeywords(&keywords, func(set *keywordSet) { set.remove(word) })
+}
+
+// SetHighlights replaces all keywords, nil removes every keyword. Patterns
+// are kept.
+func SetHighlights(words map[string]Color) {
+	updateKeywords(&keywords, func(set *keywordSet) { set.replace(words) })
+}
+
+// AddHighlightPattern colors matches of re, or only its first capture
+// group when it has one. Keywords take precedence over overlapping
+// matches and patterns over the number highlighting.
+func AddHighlightPattern(re *regexp.Regexp, color Color) {
+	updateKeywords(&keywords, func(set *keywordSet) {
+		set.patterns = append(set.patterns, pattern{re: re, color: color})
+	})
+}
+
+// AddHighlightPattern adds a pattern to lg only, see AddHighlight
+func (lg *Logger) AddHighlightPattern(re *regexp.Regexp, color Color) {
+	updateKeywords(&lg.keywords, func(set *keywordSet) {
+		set.patterns = append(set.patterns, pattern{re: re, color: color})
+	})
+}
+
+func (k *keywordSet) replace(words map[string]Color) {
+	n := newKeywordSet(words)
+	k.exact, k.folded = n.exact, n.folded
+}
+
+func (k *keywordSet) remove(word string) {
+	delete(k.exact, word)
+	delete(k.folded, strings.ToUpper(word))
+}
+
+// AddHighlight adds a keyword to lg and the loggers sharing its writers
+// only. The first change copies the package keywords, later package level
+// changes don't affect lg anymore.
+func (lg *Logger) AddHighlight(word string, color Color) {
+	updateKeywords(&lg.keywords, func(set *keywordSet) { set.exact[word] = color })
+}
+
+// RemoveHighlight removes a keyword from lg only, see AddHighlight
+func (lg *Logger) RemoveHighlight(word string) {
+	updateKeywords(&lg.keywords, func(set *keywordSet) { set.remove(word) })
+}
+
+// SetHighlights replaces the keywords of lg only, see AddHighlight
+func (lg *Logger) SetHighlights(words map[string]Color) {
+	updateKeywords(&lg.keywords, func(set *keywordSet) { set.replace(words) })
+}
+
+// keywordSet returns the keywords used by lg
+func (lg *core) keywordSet() *keywordSet {
+	if set := lg.keywords.Load(); set != nil {
+		return set
+	}
+	return keywords.Load()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// isNumber matches integers, decimals and 0x prefixed hex
+func isNumber(w string) bool {
+	if len(w) > 2 && w[0] == '0' && (w[1] == 'x' || w[1] == 'X') {
+		for i := 2; i < len(w); i++ {
+			if !isHexDigit(w[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	dot := false
+	for i := 0; i < len(w); i++ {
+		switch {
+		case isDigit(w[i]):
+		case w[i] == '.' && !dot && i > 0 && i < len(w)-1:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return len(w) > 0
+}
+
+// Units of durations as printed by time.Duration and humanDuration
+var durationUnits = []string{"ns", "us", "µs", "ms", "s", "m", "h"}
+
+// isDuration reports whether w is a decimal number with a duration unit,
+// like "1.24s" or "850µs"
+func isDuration(w string) bool {
+	for _, u := range durationUnits {
+		if n, ok := strings.CutSuffix(w, u); ok && n != "" && !strings.ContainsAny(n, "xX") && isNumber(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// span is a colored range of a message
+type span struct {
+	start, end int
+	color      Color
+}
+
+// colorString colors whole-word keywords, pattern matches and numbers in
+// that order of precedence, s is returned as is when nothing matches. All
+// ranges are collected on the raw string before any escape is inserted.
+// After each colored range the base color of the surrounding text is
+// restored.
+func colorString(set *keywordSet, s string, base Color) string {
+	var buf [16]span
+	spans := buf[:0]
+	var numbers []span
+
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			// Escapes already in the message are left alone
+			if loc := ansiRe.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				i += loc[1]
+				continue
+			}
+		}
+		if !isWordByte(s[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && isWordByte(s[i]) {
+			i++
+		}
+		// A decimal point followed by digits belongs to the number
+		if i+1 < len(s) && s[i] == '.' && isDigit(s[i+1]) && isNumber(s[start:i]) {
+			i++
+			for i < len(s) && isWordByte(s[i]) {
+				i++
+			}
+		}
+
+		word := s[start:i]
+		if color, ok := set.lookup(word); ok {
+			spans = append(spans, span{start, i, color})
+		} else if isNumber(word) || isDuration(word) {
+			numbers = append(numbers, span{start, i, Cyan}) // fallback for numbers
+		}
+	}
+
+	for _, p := range set.patterns {
+		for _, m := range p.re.FindAllStringSubmatchIndex(s, -1) {
+			// Only the first capture group is colored when there is one
+			sp := span{m[0], m[1], p.color}
+			if len(m) >= 4 && m[2] >= 0 {
+				sp.start, sp.end = m[2], m[3]
+			}
+			if sp.start < sp.end {
+				spans = addSpan(spans, sp)
+			}
+		}
+	}
+	for _, sp := range numbers {
+		spans = addSpan(spans, sp)
+	}
+
+	if len(spans) == 0 {
+		return s
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	b.Grow(len(s) + len(spans)*10)
+	last := 0
+	for _, sp := range spans {
+		b.WriteString(s[last:sp.start])
+		b.WriteString(string(sp.color))
+		b.WriteString(s[sp.start:sp.end])
+		b.WriteString(string(Reset))
+		b.WriteString(string(base))
+		last = sp.end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// addSpan appends sp unless it overlaps a span already taken
+func addSpan(spans []span, sp span) []span {
+	for _, o := range spans {
+		if sp.start < o.end && o.start < sp.end {
+			return spans
+		}
+	}
+	return append(spans, sp)
+}
@@ -0,0 +1,281 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// hl renders s in color followed by the base color, as colorString does
+func hl(color Color, s string, base Color) string {
+	return string(color) + s + string(Reset) + string(base)
+}
+
+func TestColorStringBoundaries(t *testing.T) {
+	set := newKeywordSet(highlights)
+	set.folded["TIMEOUT"] = Yellow
+	tests := []struct {
+		name string
+		in   string
+		base Color
+		want string
+	}{
+		{"inside a word", "GETTING STARTED", "", "GETTING STARTED"},
+		{"prefix of a word", "HEADER sent", "", "HEADER sent"},
+		{"underscore", "GET_ALL", "", "GET_ALL"},
+		{"whole word", "GET /x", "", hl(Green, "GET", "") + " /x"},
+		{"punctuation", "status OK.", "", "status " + hl(Green, "OK", "") + "."},
+		{"case sensitive", "ok fine", "", "ok fine"},
+		{"folded", "Timeout after TIMEOUT", "", hl(Yellow, "Timeout", "") + " after " + hl(Yellow, "TIMEOUT", "")},
+		{"base color restored", "GET done", Blue, hl(Green, "GET", Blue) + " done"},
+		{"numbers", "took 12ms for 3 items", "", "took " + hl(Cyan, "12ms", "") + " for " + hl(Cyan, "3", "") + " items"},
+		{"existing escapes", "\033[31mHEAD\033[0m", "", "\033[31m" + hl(Blue, "HEAD", "") + "\033[0m"},
+		{"no match", "nothing here", "", "nothing here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := colorString(set, tt.in, tt.base); got != tt.want {
+				t.Fatalf("colorString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkColorString(b *testing.B) {
+	set := keywords.Load()
+	tests := []struct{ name, msg string }{
+		{"no match", "user logged in from the office"},
+		{"keywords", "GET /users 200 OK in 12ms"},
+		{"long", "GETTING STARTED with the HEADER parser, no keywords in here at all but quite a lot of words"},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				colorString(set, tt.msg, "")
+			}
+		})
+	}
+}
+
+// keepKeywords restores the package keywords when the test ends
+func keepKeywords(t *testing.T) {
+	prev := keywords.Load()
+	t.Cleanup(func() { keywords.Store(prev) })
+}
+
+func TestRegisterHighlights(t *testing.T) {
+	keepKeywords(t)
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.SetPrintModule(false)
+
+	print := func(msg string) string {
+		before := len(buf.String())
+		lg.Print(msg)
+		return buf.String()[before:]
+	}
+
+	AddHighlight("RETRY", Yellow)
+	if got := print("RETRY soon"); got != hl(Yellow, "RETRY", "")+" soon\n" {
+		t.Fatalf("added keyword: %q", got)
+	}
+	RemoveHighlight("RETRY")
+	RemoveHighlight("GET")
+	if got := print("RETRY GET"); got != "RETRY GET\n" {
+		t.Fatalf("removed keywords: %q", got)
+	}
+	SetHighlights(map[string]Color{"TIMEOUT": Red})
+	if got := print("TIMEOUT OK"); got != hl(Red, "TIMEOUT", "")+" OK\n" {
+		t.Fatalf("replaced keywords: %q", got)
+	}
+}
+
+func TestLoggerHighlights(t *testing.T) {
+	keepKeywords(t)
+	http, hbuf := newTestLogger(t, WithColorMode(ColorAlways))
+	jobs, jbuf := newTestLogger(t, WithColorMode(ColorAlways))
+	http.SetPrintModule(false)
+	jobs.SetPrintModule(false)
+
+	jobs.RemoveHighlight("GET")
+	jobs.AddHighlight("QUEUED", Blue)
+	// the package set changes after the copy, jobs doesn't see it
+	AddHighlight("LATER", Red)
+
+	http.Print("GET QUEUED LATER")
+	jobs.Print("GET QUEUED LATER")
+	if want := hl(Green, "GET", "") + " QUEUED " + hl(Red, "LATER", "") + "\n"; hbuf.String() != want {
+		t.Fatalf("package keywords: %q, want %q", hbuf, want)
+	}
+	if want := "GET " + hl(Blue, "QUEUED", "") + " LATER\n"; jbuf.String() != want {
+		t.Fatalf("logger keywords: %q, want %q", jbuf, want)
+	}
+}
+
+// Run with -race
+func TestHighlightsConcurrent(t *testing.T) {
+	keepKeywords(t)
+	lg, _ := newTestLogger(t, WithColorMode(ColorAlways))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			AddHighlight("A", Red)
+			lg.AddHighlight("B", Red)
+			RemoveHighlight("A")
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		lg.Print("A B")
+	}
+	<-done
+}
+
+func TestHighlightPatterns(t *testing.T) {
+	set := newKeywordSet(highlights)
+	set.patterns = []pattern{
+		{regexp.MustCompile(`\b2\d\d\b`), Green},
+		{regexp.MustCompile(`\b5\d\d\b`), Red},
+		{regexp.MustCompile(`status=(\d+)`), Yellow},
+		{regexp.MustCompile(`GET /\S+`), Magenta},
+		{regexp.MustCompile(`\d+\.\d+\.\d+\.\d+`), Grey},
+		{regexp.MustCompile(`10\.0`), Blue},
+	}
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"pattern over number", "done 200", "done " + hl(Green, "200", "")},
+		{"capture group", "status=404", "status=" + hl(Yellow, "404", "")},
+		{"keyword over pattern", "GET /users", hl(Green, "GET", "") + " /users"},
+		{"first pattern wins", "from 10.0.0.1", "from " + hl(Grey, "10.0.0.1", "")},
+		{"several", "POST 500 status=404", hl(Blue, "POST", "") + " " + hl(Red, "500", "") + " status=" + hl(Yellow, "404", "")},
+		{"earlier pattern wins", "status=201", "status=" + hl(Green, "201", "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := colorString(set, tt.in, ""); got != tt.want {
+				t.Fatalf("colorString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddHighlightPattern(t *testing.T) {
+	keepKeywords(t)
+	a, abuf := newTestLogger(t, WithColorMode(ColorAlways))
+	b, bbuf := newTestLogger(t, WithColorMode(ColorAlways))
+	a.SetPrintModule(false)
+	b.SetPrintModule(false)
+
+	a.AddHighlightPattern(regexp.MustCompile(`\bid-\w+`), Magenta)
+	AddHighlightPattern(regexp.MustCompile(`\bjob-\w+`), Blue)
+	a.Print("id-a job-b")
+	b.Print("id-a job-b")
+
+	if want := hl(Magenta, "id-a", "") + " job-b\n"; abuf.String() != want {
+		t.Fatalf("logger pattern: %q, want %q", abuf, want)
+	}
+	if want := "id-a " + hl(Blue, "job-b", "") + "\n"; bbuf.String() != want {
+		t.Fatalf("package pattern: %q, want %q", bbuf, want)
+	}
+}
+
+func TestHighlightingLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		highlight bool
+		whole     bool
+		log       func(lg *Logger)
+		want      []string // substrings of the line
+		not       string
+	}{
+		{
+			"info", true, false, func(lg *Logger) { lg.Info("GET done") },
+			[]string{string(Blue) + "[I]   " + string(Reset), hl(Green, "GET", "") + " done\n"}, "",
+		},
+		{
+			"error", true, false, func(lg *Logger) { lg.Error("FAIL now") },
+			[]string{string(Red) + "<E> ! " + string(Reset), hl(Red, "FAIL", "") + " now\n"}, "",
+		},
+		{
+			"whole line keeps its color", true, true, func(lg *Logger) { lg.Warn("GET done") },
+			[]string{string(Yellow) + hl(Green, "GET", Yellow) + " done" + string(Reset)}, "",
+		},
+		{
+			"off", false, false, func(lg *Logger) { lg.Info("GET done") },
+			[]string{string(Reset) + "GET done\n"}, string(Green),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+			lg.SetHighlighting(tt.highlight)
+			lg.SetColorWholeLine(tt.whole)
+			tt.log(lg)
+			got := buf.String()
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Fatalf("%q doesn't contain %q", got, w)
+				}
+			}
+			if tt.not != "" && strings.Contains(got, tt.not) {
+				t.Fatalf("%q contains %q", got, tt.not)
+			}
+		})
+	}
+}
+
+func TestHighlightingNotInJSON(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways), WithJSON())
+	lg.Info("GET done 200")
+	if strings.Contains(buf.String(), "\\u001b") || !strings.Contains(buf.String(), `"msg":"GET done 200"`) {
+		t.Fatalf("JSON line = %q", buf)
+	}
+}
+
+func TestColorWholeLine(t *testing.T) {
+	const (
+		prefix = "[TEST]\033[0m\033[90m "
+		warn   = "\033[33m[W] ? \033[0m"
+		errTag = "\033[31m<E> ! \033[0m"
+	)
+	tests := []struct {
+		name      string
+		highlight bool
+		log       func(lg *Logger)
+		want      string
+	}{
+		{"warning", false, func(lg *Logger) { lg.Warn("GET done") },
+			prefix + warn + "\033[33mGET done\033[0m\n"},
+		{"keyword restores the line color", true, func(lg *Logger) { lg.Warn("GET done") },
+			prefix + warn + "\033[33m\033[32mGET\033[0m\033[33m done\033[0m\n"},
+		{"number restores the line color", true, func(lg *Logger) { lg.Error("took 12 ms") },
+			prefix + errTag + "\033[31mtook \033[36m12\033[0m\033[31m ms\033[0m\n"},
+		{"fields keep their color", false, func(lg *Logger) { lg.WithField("k", 1).Error("x") },
+			prefix + errTag + "\033[31mx\033[0m\033[90m k=1\033[0m\n"},
+		{"info untouched", true, func(lg *Logger) { lg.Info("GET done") },
+			prefix + "\033[34m[I]   \033[0m\033[32mGET\033[0m done\n"},
+		{"print untouched", false, func(lg *Logger) { lg.Print("GET done") },
+			prefix + "GET done\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+			lg.SetHighlighting(tt.highlight)
+			lg.SetColorWholeLine(true)
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.SetHighlighting(false)
+	lg.Warn("GET done")
+	if want := prefix + warn + "GET done\n"; buf.String() != want {
+		t.Fatalf("off by default: got %q, want %q", buf, want)
+	}
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Hook is called for every written message. Hooks run on the consumer
+// goroutine before the message is written, so they must be fast or a
+// timeout has to be set with SetHookTimeout.
+type Hook func(level LogLevel, module string, msg string, fields map[string]any)
+
+// HookID identifies a hook for RemoveHook
+type HookID int
+
+type hookEntry struct {
+	id HookID
+	fn Hook
+}
+
+// AddHook registers a hook shared by lg and its derived loggers
+func (lg *Logger) AddHook(fn Hook) HookID {
+	lg.hooksMu.Lock()
+	defer lg.hooksMu.Unlock()
+
+	lg.nextHook++
+	lg.hooks = append(lg.hooks, hookEntry{id: lg.nextHook, fn: fn})
+	return lg.nextHook
+}
+
+// RemoveHook unregisters a hook, unknown ids are ignored
+func (lg *Logger) RemoveHook(id HookID) {
+	lg.hooksMu.Lock()
+	defer lg.hooksMu.Unlock()
+
+	for i, h := range lg.hooks {
+		if h.id == id {
+			lg.hooks = append(lg.hooks[:i:i], lg.hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetHookTimeout limits how long a single hook may block the logger, a
+// hook that takes longer keeps running in the background. 0 (the default)
+// runs hooks inline.
+func (lg *Logger) SetHookTimeout(d time.Duration) {
+	lg.hookTimeout.Store(int64(d))
+}
+
+func (lg *core) runHooks(m logMessage) {
+	lg.hooksMu.RLock()
+	hooks := lg.hooks
+	lg.hooksMu.RUnlock()
+	if len(hooks) > 0 {
+		lg.fireHooks(hooks, m)
+	}
+}
+
+// fireHooks is kept apart from runHooks, m escapes to the goroutines here
+// and would cost an allocation per message even without hooks
+func (lg *core) fireHooks(hooks []hookEntry, m logMessage) {
+	fields := make(map[string]any, len(m.fields))
+	for _, f := range m.fields {
+		fields[f.Key] = hookValue(f.Value)
+	}
+
+	timeout := time.Duration(lg.hookTimeout.Load())
+	for _, h := range hooks {
+		if timeout <= 0 {
+			lg.callHook(h.fn, m, fields)
+			continue
+		}
+
+		done := make(chan struct{})
+		go func() {
+			lg.callHook(h.fn, m, fields)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			lg.printer(lg.internalMessage(m, LevelWarn, fmt.Sprintf("hook %d timed out after %s", h.id, timeout)))
+		}
+	}
+}
+
+// callHook runs a hook so that a panic in it can't kill the consumer
+func (lg *core) callHook(fn Hook, m logMessage, fields map[string]any) {
+	defer func() {
+		if r := recover(); r != nil {
+			lg.printer(lg.internalMessage(m, LevelError, fmt.Sprint("hook panicked: ", r)))
+		}
+	}()
+	fn(m.level, m.module, m.msg, fields)
+}
+
+// internalMessage returns a message from the logger itself attributed to
+// the module of m
+func (lg *core) internalMessage(m logMessage, level LogLevel, msg string) logMessage {
+	return logMessage{
+		level:    level,
+		msg:      msg,
+		time:     lg.now(),
+		module:   m.module,
+		color:    m.color,
+		internal: true,
+	}
+}
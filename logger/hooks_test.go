@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// hookRecorder collects what a hook was called with
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *hookRecorder) hook(name string) Hook {
+	return func(level LogLevel, module string, msg string, fields map[string]any) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, fmt.Sprintf("%s %v %s %s %v", name, level, module, msg, fields))
+	}
+}
+
+func (r *hookRecorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, "\n")
+}
+
+func TestHooks(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(lg *Logger, r *hookRecorder)
+		want []string
+	}{
+		{
+			"several hooks",
+			func(lg *Logger, r *hookRecorder) {
+				lg.AddHook(r.hook("a"))
+				lg.AddHook(r.hook("b"))
+				lg.WithField("k", 1).Error("boom")
+			},
+			[]string{"a error TEST boom map[k:1]", "b error TEST boom map[k:1]"},
+		},
+		{
+			"removed",
+			func(lg *Logger, r *hookRecorder) {
+				id := lg.AddHook(r.hook("a"))
+				lg.AddHook(r.hook("b"))
+				lg.Info("one")
+				lg.RemoveHook(id)
+				lg.RemoveHook(id)
+				lg.Info("two")
+			},
+			[]string{"a info TEST one map[]", "b info TEST one map[]", "b info TEST two map[]"},
+		},
+		{
+			"filtered levels",
+			func(lg *Logger, r *hookRecorder) {
+				lg.AddHook(r.hook("a"))
+				lg.SetLevel(LevelWarn)
+				lg.Info("hidden")
+				lg.Warn("shown")
+			},
+			[]string{"a warn TEST shown map[]"},
+		},
+		{
+			"derived share hooks",
+			func(lg *Logger, r *hookRecorder) {
+				lg.AddHook(r.hook("a"))
+				lg.Sub("DB", Blue).Info("q")
+			},
+			[]string{"a info TEST/DB q map[]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, _ := newTestLogger(t)
+			r := &hookRecorder{}
+			tt.run(lg, r)
+			if got := r.String(); got != strings.Join(tt.want, "\n") {
+				t.Fatalf("calls:\n%s\nwant:\n%s", got, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestHookPanic(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	r := &hookRecorder{}
+	lg.AddHook(func(LogLevel, string, string, map[string]any) { panic("bad hook") })
+	lg.AddHook(r.hook("after"))
+	lg.Info("one")
+	lg.Info("two")
+
+	out := buf.String()
+	if strings.Count(out, "hook panicked: bad hook") != 2 || !strings.Contains(out, "two") {
+		t.Fatalf("output:\n%s", out)
+	}
+	if !strings.Contains(r.String(), "after info TEST two") {
+		t.Fatalf("hooks after the panicking one weren't called: %s", r)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetHookTimeout(10 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	lg.AddHook(func(LogLevel, string, string, map[string]any) { <-release })
+
+	start := time.Now()
+	lg.Info("slow")
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("a blocked hook held the logger for %s", d)
+	}
+	if !strings.Contains(buf.String(), "timed out after 10ms") || !strings.Contains(buf.String(), "slow") {
+		t.Fatalf("output:\n%s", buf)
+	}
+}
+
+func TestHookCallsLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(lg *Logger)
+	}{
+		{"Flush", func(lg *Logger) { lg.Flush() }},
+		{"AddWriter", func(lg *Logger) { lg.AddWriter(&syncBuffer{}) }},
+		{"RemoveWriter", func(lg *Logger) { lg.RemoveWriter(lg.AddWriter(&syncBuffer{})) }},
+		{"SetOutput", func(lg *Logger) { lg.SetOutput(&syncBuffer{}) }},
+		{"AddFilter", func(lg *Logger) { lg.AddFilter(FilterRule{Pattern: regexp.MustCompile("x")}) }},
+		{"SetCollapse", func(lg *Logger) { lg.SetCollapse(time.Second, time.Second) }},
+		{"Fatal", func(lg *Logger) { lg.Fatal("from hook") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg := NewLogger("TEST", WithWriters(&syncBuffer{}), WithNoColor())
+			exited := make(chan int, 1)
+			lg.SetExitFunc(func(code int) { exited <- code })
+			var called atomic.Bool
+			done := make(chan struct{})
+			lg.AddHook(func(LogLevel, string, string, map[string]any) {
+				if called.CompareAndSwap(false, true) {
+					tt.call(lg)
+					close(done)
+				}
+			})
+			lg.Info("trigger")
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("deadlocked calling the logger from a hook")
+			}
+			if tt.name == "Fatal" && len(exited) != 1 {
+				t.Fatal("Fatal from a hook didn't exit")
+			}
+			lg.Close()
+		})
+	}
+}
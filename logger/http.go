@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTPOption configures HTTPMiddleware
+type HTTPOption func(*httpConfig)
+
+type httpConfig struct {
+	skip map[string]bool
+	slow time.Duration
+}
+
+// HTTPSkipPaths disables logging of requests to the given paths, like
+// health checks
+func HTTPSkipPaths(paths ...string) HTTPOption {
+	return func(c *httpConfig) {
+		for _, p := range paths {
+			c.skip[p] = true
+		}
+	}
+}
+
+// HTTPSlowThreshold logs successful requests faster than d at Debug, so
+// only slow ones show up at Info
+func HTTPSlowThreshold(d time.Duration) HTTPOption {
+	return func(c *httpConfig) { c.slow = d }
+}
+
+// HTTPMiddleware logs method, path, status, response size and latency of
+// every request. Client errors are logged at Warn and server errors at
+// Error.
+func HTTPMiddleware(lg *Logger, opts ...HTTPOption) func(http.Handler) http.Handler {
+	c := &httpConfig{skip: map[string]bool{}}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w}
+			start := time.Now()
+			next.ServeHTTP(rw, r)
+			elapsed := time.Since(start)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if rw.hijacked {
+				lg.logfDepth(1, LevelInfo, "%s %s hijacked %s", r.Method, r.URL.Path, elapsed)
+				return
+			}
+
+			level := LevelInfo
+			switch {
+			case status >= 500:
+				level = LevelError
+			case status >= 400:
+				level = LevelWarn
+			case c.slow > 0 && elapsed < c.slow:
+				level = LevelDebug
+			}
+			lg.logfDepth(1, level, "%s %s %d %dB %s", r.Method, r.URL.Path, status, rw.size, elapsed)
+		})
+	}
+}
+
+// responseWriter records the status and size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status   int
+	size     int64
+	hijacked bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Flush keeps streaming responses working
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack keeps websocket upgrades working
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("couldn't hijack connection: %T is not a http.Hijacker", w.ResponseWriter)
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the original writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RecoverHandler recovers panics in next, logs the value with the stack and
+// the request at Error and responds 500 unless something was written
+// already. http.ErrAbortHandler is passed on untouched. Wrap it with
+// HTTPMiddleware so panicked requests still get their access line:
+//
+//	HTTPMiddleware(lg)(RecoverHandler(lg, mux))
+func RecoverHandler(lg *Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw, ok := w.(*responseWriter)
+		if !ok {
+			rw = &responseWriter{ResponseWriter: w}
+		}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			if lg.enabled(LevelError) || lg.ring.Load() != nil {
+				m := lg.message(LevelError, fmt.Sprintf("panic in %s %s: %v", r.Method, r.URL.Path, v))
+				m.stack = stack(1, int(lg.stackDepth.Load()))
+				if lg.enabled(LevelError) {
+					lg.dispatch(m, true)
+				} else {
+					lg.record(m)
+				}
+			}
+			if rw.status == 0 && !rw.hijacked {
+				http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []HTTPOption
+		path    string
+		handler http.HandlerFunc
+		want    string // regexp of the logged line, empty for none
+	}{
+		{
+			"ok", nil, "/users",
+			func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("hello")) },
+			`^\[I\]   GET /users 200 5B \S+\n$`,
+		},
+		{
+			"not found", nil, "/missing",
+			http.NotFound,
+			`^\[W\] \? GET /missing 404 19B \S+\n$`,
+		},
+		{
+			"server error", nil, "/boom",
+			func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(500) },
+			`^<E> ! GET /boom 500 0B \S+\n$`,
+		},
+		{
+			"skipped", []HTTPOption{HTTPSkipPaths("/healthz")}, "/healthz",
+			func(w http.ResponseWriter, r *http.Request) {},
+			``,
+		},
+		{
+			"fast below threshold", []HTTPOption{HTTPSlowThreshold(time.Hour)}, "/fast",
+			func(w http.ResponseWriter, r *http.Request) {},
+			`^\[D\]   GET /fast 200 0B \S+\n$`,
+		},
+		{
+			"slow above threshold", []HTTPOption{HTTPSlowThreshold(time.Millisecond)}, "/slow",
+			func(w http.ResponseWriter, r *http.Request) { time.Sleep(2 * time.Millisecond) },
+			`^\[I\]   GET /slow 200 0B \S+\n$`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			h := HTTPMiddleware(lg, tt.opts...)(tt.handler)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.path, nil))
+
+			got := buf.String()
+			if tt.want == "" {
+				if got != "" {
+					t.Fatalf("logged %q", got)
+				}
+				return
+			}
+			if !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Fatalf("logged %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetPrintModule(false)
+	srv := httptest.NewServer(HTTPMiddleware(lg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("wrapped writer isn't a http.Flusher")
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		rw.Flush()
+	})))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	srv.Close()
+
+	if got := buf.String(); !regexp.MustCompile(`^\[I\]   GET /ws hijacked \S+\n$`).MatchString(got) {
+		t.Fatalf("logged %q", got)
+	}
+}
+
+func TestRecoverHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware bool
+		handler    http.HandlerFunc
+		panics     any    // passed on to the server
+		code       int    // of the response
+		body       string // of the response
+		want       string // regexp of the log output, empty for none
+	}{
+		{
+			name:    "panic",
+			handler: func(w http.ResponseWriter, r *http.Request) { panic("kaboom") },
+			code:    500,
+			body:    "Internal Server Error\n",
+			want:    `^<E> ! panic in GET /boom: kaboom\n(?s:.*)http_test\.go`,
+		},
+		{
+			name: "partially written",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("partial"))
+				panic(errors.New("mid response"))
+			},
+			code: 200,
+			body: "partial",
+			want: `^<E> ! panic in GET /boom: mid response\n`,
+		},
+		{
+			name:       "with the access log",
+			middleware: true,
+			handler:    func(w http.ResponseWriter, r *http.Request) { panic("kaboom") },
+			code:       500,
+			body:       "Internal Server Error\n",
+			want:       `^<E> ! panic in GET /boom: kaboom\n(?s:.*)\n<E> ! GET /boom 500 22B \S+\n$`,
+		},
+		{
+			name:    "abort handler",
+			handler: func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) },
+			panics:  http.ErrAbortHandler,
+			code:    200,
+		},
+		{
+			name:    "no panic",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) },
+			code:    200,
+			body:    "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			h := RecoverHandler(lg, tt.handler)
+			if tt.middleware {
+				h = HTTPMiddleware(lg)(h)
+			}
+			rec := httptest.NewRecorder()
+			func() {
+				defer func() {
+					if v := recover(); v != tt.panics {
+						t.Errorf("panic = %v, want %v", v, tt.panics)
+					}
+				}()
+				h.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+			}()
+
+			if rec.Code != tt.code || rec.Body.String() != tt.body {
+				t.Errorf("response = %d %q, want %d %q", rec.Code, rec.Body, tt.code, tt.body)
+			}
+			got := buf.String()
+			if tt.want == "" {
+				if got != "" {
+					t.Fatalf("logged %q", got)
+				}
+				return
+			}
+			if !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Fatalf("logged %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecoverHandlerHighlightsMethod(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	h := RecoverHandler(lg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("kaboom") }))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+
+	if want := "panic in " + hl(Green, "GET", "") + " /boom"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("logged %q, want %q", buf, want)
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Bytes formats n in binary units with one decimal, like "1.4 GiB".
+// Values below 1 KiB are printed as is, like "999 B".
+func Bytes(n int64) string {
+	if n > -1024 && n < 1024 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	sign := ""
+	if n < 0 {
+		sign = "-" // not -n, which overflows for math.MinInt64
+	}
+	v := math.Abs(float64(n))
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	// Compare the rounded value so 1048575 is "1.0 MiB", not "1024.0 KiB"
+	for math.Round(v*10) >= 1024*10 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return sign + strconv.FormatFloat(v, 'f', 1, 64) + " " + units[i]
+}
+
+// Duration formats d with its two largest units, like "2m13s" or "1h5m".
+// Durations below a minute keep three significant digits, like "1.24s" or
+// "850µs".
+func Duration(d time.Duration) string {
+	if d < 0 {
+		return "-" + Duration(-d)
+	}
+	if d == 0 {
+		return "0s"
+	}
+	if r := roundSignificant(d); r < time.Minute {
+		return humanDuration(r)
+	}
+
+	d = d.Round(time.Second)
+	h, m, s := int64(d/time.Hour), int64(d%time.Hour/time.Minute), int64(d%time.Minute/time.Second)
+	if h > 0 {
+		out := strconv.FormatInt(h, 10) + "h"
+		if m > 0 {
+			out += strconv.FormatInt(m, 10) + "m"
+		}
+		return out
+	}
+	out := strconv.FormatInt(m, 10) + "m"
+	if s > 0 {
+		out += strconv.FormatInt(s, 10) + "s"
+	}
+	return out
+}
+
+// Rate formats the throughput of bytes transferred in d, like "10.8 MiB/s"
+func Rate(bytes int64, d time.Duration) string {
+	if d <= 0 {
+		return "0 B/s"
+	}
+	return Bytes(int64(float64(bytes)/d.Seconds())) + "/s"
+}
+
+// humanizePatterns highlights the output of Bytes, Rate and Duration
+func humanizePatterns() []pattern {
+	return []pattern{
+		{regexp.MustCompile(`\b\d+(?:\.\d+)? (?:B|KiB|MiB|GiB|TiB|PiB|EiB)(?:/s)?\b`), Cyan},
+		{regexp.MustCompile(`\b\d+h(?:\d+m)?\b|\b\d+m\d+s\b`), Cyan},
+	}
+}
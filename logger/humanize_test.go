@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1<<20 - 1, "1.0 MiB"},
+		{1 << 20, "1.0 MiB"},
+		{1503238553, "1.4 GiB"},
+		{math.MaxInt64, "8.0 EiB"},
+		{-512, "-512 B"},
+		{-2048, "-2.0 KiB"},
+		{math.MinInt64, "-8.0 EiB"},
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.n); got != tt.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{1, "1ns"},
+		{999, "999ns"},
+		{1000, "1.00µs"},
+		{850 * time.Microsecond, "850µs"},
+		{999999, "1.00ms"},
+		{1500 * time.Millisecond, "1.50s"},
+		{59999 * time.Millisecond, "1m"},
+		{time.Minute, "1m"},
+		{133 * time.Second, "2m13s"},
+		{3 * time.Hour, "3h"},
+		{65*time.Minute + 40*time.Second, "1h5m"},
+		{3599*time.Second + 600*time.Millisecond, "1h"},
+		{-2 * time.Second, "-2.00s"},
+		{-90 * time.Second, "-1m30s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		d     time.Duration
+		want  string
+	}{
+		{1503238553, 133 * time.Second, "10.8 MiB/s"},
+		{100, 2 * time.Second, "50 B/s"},
+		{1, time.Hour, "0 B/s"},
+		{5, 0, "0 B/s"},
+		{5, -time.Second, "0 B/s"},
+		{0, time.Second, "0 B/s"},
+	}
+	for _, tt := range tests {
+		if got := Rate(tt.bytes, tt.d); got != tt.want {
+			t.Errorf("Rate(%d, %v) = %q, want %q", tt.bytes, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestHumanizeHighlighting(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"transfer", "uploaded " + Bytes(1503238553) + " in " + Duration(133*time.Second) + " (" + Rate(1503238553, 133*time.Second) + ")",
+			"uploaded " + hl(Cyan, "1.4 GiB", "") + " in " + hl(Cyan, "2m13s", "") + " (" + hl(Cyan, "10.8 MiB/s", "") + ")"},
+		{"hours", "took 1h5m", "took " + hl(Cyan, "1h5m", "")},
+		{"bytes", "wrote 512 B", "wrote " + hl(Cyan, "512 B", "")},
+		{"not a size", "512 Bananas", hl(Cyan, "512", "") + " Bananas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+			lg.SetPrintModule(false)
+			lg.Print(tt.msg)
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Fatalf("got  %q\nwant %q", got, tt.want+"\n")
+			}
+		})
+	}
+}
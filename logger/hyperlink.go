@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// linkID is the random prefix of the ids of links made by Hyperlink,
+// sanitize drops links without it so logged input can't forge them
+var (
+	linkID = func() string {
+		b := make([]byte, 8)
+		rand.Read(b)
+		return "lg" + hex.EncodeToString(b) + "-"
+	}()
+	linkSeq atomic.Uint64
+)
+
+// Hyperlink makes v a clickable link to url on terminals supporting OSC 8.
+// Other destinations get "text (url)", see SetHyperlinks. Control
+// characters are removed from url.
+func Hyperlink(url string, v ...any) string {
+	url = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, url)
+	return fmt.Sprintf("\033]8;id=%s%d;%s\033\\%s\033]8;;\033\\", linkID, linkSeq.Add(1), url, fmt.Sprint(v...))
+}
+
+// trustedLink reports whether the OSC 8 sequence seq opens a link made by
+// Hyperlink
+func trustedLink(seq string) bool {
+	params, _, _ := strings.Cut(strings.TrimPrefix(seq, "\x1b]8;"), ";")
+	for _, p := range strings.Split(params, ":") {
+		if strings.HasPrefix(p, "id="+linkID) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHyperlinks decides where links made by Hyperlink are kept. ColorAuto
+// keeps them on colored writers when the terminal is known to support
+// them, the other writers get the "text (url)" fallback.
+func (lg *Logger) SetHyperlinks(mode ColorMode) {
+	lg.updateOpts(func(o *renderOptions) { o.hyperlinks = mode })
+}
+
+// links reports whether hyperlinks are kept in the colored or plain
+// variant of a line
+func (o *renderOptions) links(color bool) bool {
+	switch o.hyperlinks {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return color && terminalHyperlinks()
+}
+
+// terminalHyperlinks is detectHyperlinks, looked up once
+var terminalHyperlinks = sync.OnceValue(detectHyperlinks)
+
+// detectHyperlinks guesses from the environment whether the terminal
+// renders OSC 8 links
+func detectHyperlinks() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	term := os.Getenv("TERM")
+	for _, t := range []string{"kitty", "foot", "alacritty", "wezterm"} {
+		if strings.Contains(term, t) {
+			return true
+		}
+	}
+	return false
+}
+
+var linkRe = regexp.MustCompile("\x1b\\]8;[^;\x07\x1b]*;([^\x07\x1b]*)(?:\x07|\x1b\\\\)(.*?)\x1b\\]8;;(?:\x07|\x1b\\\\)")
+
+// unlink replaces OSC 8 links with "text (url)", or the url alone when the
+// text is empty or the url itself
+func unlink(s string) string {
+	if !strings.Contains(s, "\x1b]8;") {
+		return s
+	}
+	return linkRe.ReplaceAllStringFunc(s, func(link string) string {
+		m := linkRe.FindStringSubmatch(link)
+		url, text := m[1], m[2]
+		if text == "" || text == url {
+			return url
+		}
+		return text + " (" + url + ")"
+	})
+}
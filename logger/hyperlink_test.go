@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnlink(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no link", "plain text", "plain text"},
+		{"text and url", "see " + Hyperlink("https://example.com", "docs") + " now", "see docs (https://example.com) now"},
+		{"empty text", Hyperlink("https://example.com"), "https://example.com"},
+		{"text is the url", Hyperlink("https://example.com", "https://example.com"), "https://example.com"},
+		{"bel terminated", "\x1b]8;;https://a.example\x07a\x1b]8;;\x07", "a (https://a.example)"},
+		{"two links", Hyperlink("https://a.example", "a") + " " + Hyperlink("https://b.example", "b"), "a (https://a.example) b (https://b.example)"},
+		{"colored text", Hyperlink("https://example.com", ColorString(Red, "red")), ColorString(Red, "red") + " (https://example.com)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unlink(tt.in); got != tt.want {
+				t.Fatalf("unlink(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHyperlinkModes(t *testing.T) {
+	link := Hyperlink("https://example.com", "docs")
+	tests := []struct {
+		name     string
+		mode     ColorMode
+		color    bool
+		terminal bool // whether the terminal supports links
+		keep     bool
+	}{
+		{"always plain", ColorAlways, false, false, true},
+		{"always colored", ColorAlways, true, false, true},
+		{"never colored", ColorNever, true, true, false},
+		{"auto plain", ColorAuto, false, true, false},
+		{"auto colored, supported", ColorAuto, true, true, true},
+		{"auto colored, unsupported", ColorAuto, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := terminalHyperlinks
+			terminalHyperlinks = func() bool { return tt.terminal }
+			defer func() { terminalHyperlinks = old }()
+
+			mode := ColorNever
+			if tt.color {
+				mode = ColorAlways
+			}
+			lg, buf := newTestLogger(t, WithColorMode(mode))
+			lg.SetHyperlinks(tt.mode)
+			lg.Info(link)
+
+			out := buf.String()
+			if got := strings.Contains(out, link); got != tt.keep {
+				t.Fatalf("link kept = %v in %q", got, out)
+			}
+			if !tt.keep && !strings.Contains(stripANSI(out), "docs (https://example.com)") {
+				t.Fatalf("no fallback in %q", out)
+			}
+			if !tt.keep && strings.Contains(out, "\x1b]8;") {
+				t.Fatalf("OSC 8 left in %q", out)
+			}
+		})
+	}
+}
+
+func TestDetectHyperlinks(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"nothing", nil, false},
+		{"dumb", map[string]string{"TERM": "dumb"}, false},
+		{"xterm", map[string]string{"TERM": "xterm-256color"}, false},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{"vscode", map[string]string{"TERM_PROGRAM": "vscode"}, true},
+		{"windows terminal", map[string]string{"WT_SESSION": "1b2c"}, true},
+		{"konsole", map[string]string{"KONSOLE_VERSION": "220401"}, true},
+		{"new vte", map[string]string{"VTE_VERSION": "6003"}, true},
+		{"old vte", map[string]string{"VTE_VERSION": "4205"}, false},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"TERM", "TERM_PROGRAM", "WT_SESSION", "KONSOLE_VERSION", "VTE_VERSION"} {
+				t.Setenv(env, tt.env[env])
+			}
+			if got := detectHyperlinks(); got != tt.want {
+				t.Fatalf("detectHyperlinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
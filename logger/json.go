@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Output format of a Logger
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+	FormatLogfmt
+)
+
+// jsonReserved are the keys written by writeJSON itself
+var jsonReserved = map[string]bool{
+	"time": true, "level": true, "module": true, "msg": true,
+	"caller": true, "error": true, "error_chain": true, "stack": true,
+}
+
+// writeJSON writes the message as a single JSON object line, without any
+// ANSI escapes. Fields named like a reserved key are written as
+// "fields.<key>" so every key is unique.
+func (lg *core) writeJSON(b *bytes.Buffer, m logMessage, now time.Time) {
+	b.WriteString(`{"time":`)
+	writeJSONValue(b, lg.opts.Load().jsonTime(now))
+	b.WriteString(`,"level":`)
+	writeJSONValue(b, levelNames[m.level])
+	b.WriteString(`,"module":`)
+	writeJSONValue(b, m.module)
+	b.WriteString(`,"msg":`)
+	writeJSONValue(b, stripANSI(unlink(m.msg)))
+	if m.caller != "" {
+		b.WriteString(`,"caller":`)
+		writeJSONValue(b, m.caller)
+	}
+	if m.err != nil {
+		b.WriteString(`,"error":`)
+		writeJSONValue(b, m.err.Error())
+		b.WriteString(`,"error_chain":`)
+		writeJSONValue(b, errorChain(m.err))
+	}
+	if len(m.stack) > 0 {
+		b.WriteString(`,"stack":`)
+		writeJSONValue(b, m.stack)
+	}
+	for _, f := range m.fields {
+		key := f.Key
+		if jsonReserved[key] {
+			key = "fields." + key
+		}
+		b.WriteByte(',')
+		writeJSONValue(b, key)
+		b.WriteByte(':')
+		writeJSONValue(b, f.Value)
+	}
+	b.WriteString("}\n")
+}
+
+// writeJSONValue encodes v, falling back to its string form when it can't
+// be marshaled
+func writeJSONValue(b *bytes.Buffer, v any) {
+	v = machineValue(v)
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
+
+// JSONBlock logs v as indented JSON at Debug, the lines after the first
+// are marked as continuation in text output
+func (lg *Logger) JSONBlock(v any) {
+	if !lg.enabled(LevelDebug) && lg.ring.Load() == nil {
+		return
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		lg.logDepth(1, LevelError, "couldn't marshal JSON block: ", err)
+		return
+	}
+	lg.logDepth(1, LevelDebug, string(b))
+}
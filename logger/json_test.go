@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// jsonKeys returns the keys of the JSON object line in order, failing on
+// duplicates
+func jsonKeys(t *testing.T, line string) []string {
+	t.Helper()
+
+	dec := json.NewDecoder(strings.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	var keys []string
+	seen := map[string]bool{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		key := tok.(string)
+		if seen[key] {
+			t.Fatalf("duplicate key %q in %q", key, line)
+		}
+		seen[key] = true
+		keys = append(keys, key)
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+	}
+	return keys
+}
+
+func TestJSONOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want map[string]any
+	}{
+		{
+			name: "message",
+			log:  func(lg *Logger) { lg.Info("hello world") },
+			want: map[string]any{"level": "info", "module": "TEST", "msg": "hello world"},
+		},
+		{
+			name: "colors stripped",
+			log:  func(lg *Logger) { lg.Info("red ", ColorString(Red, "alert")) },
+			want: map[string]any{"msg": "red alert"},
+		},
+		{
+			name: "hyperlink",
+			log:  func(lg *Logger) { lg.Info(Hyperlink("https://example.com", "docs")) },
+			want: map[string]any{"msg": "docs (https://example.com)"},
+		},
+		{
+			name: "fields",
+			log:  func(lg *Logger) { lg.WithField("user", "ann").WithField("n", 2).Info("x") },
+			want: map[string]any{"user": "ann", "n": float64(2)},
+		},
+		{
+			name: "reserved keys",
+			log:  func(lg *Logger) { lg.WithField("msg", "dup").WithField("level", "x").Warn("real") },
+			want: map[string]any{"msg": "real", "level": "warn", "fields.msg": "dup", "fields.level": "x"},
+		},
+		{
+			name: "error",
+			log:  func(lg *Logger) { lg.Err(errors.New("broken"), "failed") },
+			want: map[string]any{"level": "error", "error": "broken"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithJSON())
+			tt.log(lg)
+
+			line := buf.String()
+			jsonKeys(t, line)
+			var got map[string]any
+			if err := json.Unmarshal([]byte(line), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", line, err)
+			}
+			if _, ok := got["time"]; !ok {
+				t.Errorf("no time in %q", line)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %#v, want %#v in %q", k, got[k], v, line)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		level LogLevel
+		v     any
+		want  string
+	}{
+		{"indented", LevelDebug, map[string]any{"a": 1, "b": []int{1}},
+			"[D]   {\n    │   \"a\": 1,\n    │   \"b\": [\n    │     1\n    │   ]\n    │ }\n"},
+		{"scalar", LevelDebug, 42, "[D]   42\n"},
+		{"filtered", LevelInfo, map[string]any{"a": 1}, ""},
+		{"unsupported", LevelDebug, func() {}, "<E> ! couldn't marshal JSON block: json: unsupported type: func()\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(tt.level))
+			lg.SetPrintModule(false)
+			lg.JSONBlock(tt.v)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
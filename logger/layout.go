@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// layoutPart is a literal or a placeholder of a prefix format
+type layoutPart struct {
+	literal     string
+	placeholder string // "module", "level" or "time"
+}
+
+// parseLayout splits format into literals and placeholders
+func parseLayout(format string) ([]layoutPart, error) {
+	var parts []layoutPart
+	for format != "" {
+		i := strings.IndexByte(format, '{')
+		if i < 0 {
+			parts = append(parts, layoutPart{literal: format})
+			break
+		}
+		if i > 0 {
+			parts = append(parts, layoutPart{literal: format[:i]})
+		}
+		j := strings.IndexByte(format[i:], '}')
+		if j < 0 {
+			return nil, fmt.Errorf("couldn't parse prefix format: unclosed %q", format[i:])
+		}
+		switch name := format[i+1 : i+j]; name {
+		case "module", "level", "time":
+			parts = append(parts, layoutPart{placeholder: name})
+		default:
+			return nil, fmt.Errorf("couldn't parse prefix format: unknown placeholder {%s}", name)
+		}
+		format = format[i+j+1:]
+	}
+	return parts, nil
+}
+
+// SetPrefixFormat replaces the "[MODULE] time tag" layout of text output
+// with format, where {module}, {level} and {time} are replaced and
+// anything else is kept as is, e.g. "{time} {level} {module}: ". The
+// message follows right after it. SetPrintModule and SetPrintTime still
+// hide their placeholder, an empty format restores the default layout.
+// Messages already queued keep the previous layout.
+func (lg *Logger) SetPrefixFormat(format string) error {
+	var parts []layoutPart
+	if format != "" {
+		var err error
+		if parts, err = parseLayout(format); err != nil {
+			return err
+		}
+	}
+	lg.control(func() {
+		lg.updateOpts(func(o *renderOptions) { o.layout = parts })
+	})
+	return nil
+}
+
+// SetLevelTag changes the text marking level in text output, like "info: "
+// instead of "[I]   ", keeping the color of the theme
+func (lg *Logger) SetLevelTag(level LogLevel, tag string) {
+	lg.control(func() {
+		lg.updateOpts(func(o *renderOptions) {
+			t := *o.theme
+			t.Levels = make(map[LogLevel]LevelTag, len(o.theme.Levels))
+			for l, lt := range o.theme.Levels {
+				t.Levels[l] = lt
+			}
+			lt := t.Levels[level]
+			lt.Text = tag
+			t.Levels[level] = lt
+			o.theme = &t
+		})
+	})
+}
+
+// renderLayout writes the prefix format of o for m to b
+func (lg *core) renderLayout(b *bytes.Buffer, o *renderOptions, m logMessage, now time.Time, color bool) {
+	t := o.theme
+	for _, p := range o.layout {
+		switch p.placeholder {
+		case "":
+			b.WriteString(p.literal)
+		case "module":
+			if !o.printModule {
+				continue
+			}
+			if color {
+				mc := m.color
+				if t.ModuleColor != "" {
+					mc = t.ModuleColor
+				}
+				b.WriteString(string(mc) + m.module + string(Reset))
+			} else {
+				b.WriteString(m.module)
+			}
+			b.WriteString(padModule(m.module))
+		case "level":
+			tag, ok := t.Levels[m.level]
+			if !ok {
+				continue
+			}
+			if color {
+				b.WriteString(string(tag.Color) + tag.Text + string(Reset))
+			} else {
+				b.WriteString(tag.Text)
+			}
+		case "time":
+			if !o.printTime {
+				continue
+			}
+			if color {
+				b.WriteString(string(t.Prefix) + o.timestamp(now) + string(Reset))
+			} else {
+				b.WriteString(o.timestamp(now))
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrefixFormat(t *testing.T) {
+	levels := []LogLevel{LevelTrace, LevelPrint, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal}
+	tests := []struct {
+		name   string
+		format string
+		tags   bool // lowercase level names followed by ":"
+		time   bool
+		want   []string
+	}{
+		{"house style", "{time} {level} {module} ", true, true, []string{
+			"2026/10/14 09:05:07 trace: TEST m",
+			"2026/10/14 09:05:07 print: TEST m",
+			"2026/10/14 09:05:07 debug: TEST m",
+			"2026/10/14 09:05:07 info: TEST m",
+			"2026/10/14 09:05:07 warn: TEST m",
+			"2026/10/14 09:05:07 error: TEST m",
+			"2026/10/14 09:05:07 fatal: TEST m",
+		}},
+		{"default tags", "{module} | {level}", false, false, []string{
+			"TEST | [T]   m",
+			"TEST | m",
+			"TEST | [D]   m",
+			"TEST | [I]   m",
+			"TEST | [W] ? m",
+			"TEST | <E> ! m",
+			"TEST | <F>!!! m",
+		}},
+		{"time hidden", "{time}|{module}: ", false, false, []string{
+			"|TEST: m", "|TEST: m", "|TEST: m", "|TEST: m", "|TEST: m", "|TEST: m", "|TEST: m",
+		}},
+		{"no placeholders", "> ", false, true, []string{
+			"> m", "> m", "> m", "> m", "> m", "> m", "> m",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelTrace), WithPrintTime(tt.time))
+			lg.SetExitFunc(func(int) {})
+			lg.SetClock(func() time.Time { return time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local) })
+			if err := lg.SetPrefixFormat(tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tags {
+				for _, l := range levels {
+					lg.SetLevelTag(l, strings.ToLower(l.String())+":")
+				}
+			}
+			for _, l := range levels {
+				lg.Log(l, "m")
+			}
+
+			if got, want := buf.String(), strings.Join(tt.want, "\n")+"\n"; got != want {
+				t.Fatalf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestPrefixFormatColors(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways), WithColor(Blue), WithPrintTime(true))
+	lg.SetClock(func() time.Time { return time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local) })
+	lg.SetHighlighting(false)
+	if err := lg.SetPrefixFormat("{level}{module} {time} | "); err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("x")
+
+	want := "\033[34m[I]   \033[0m\033[34mTEST\033[0m \033[90m2026/10/14 09:05:07\033[0m | x\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestPrefixFormatErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		err    string
+	}{
+		{"{mod} ", "unknown placeholder {mod}"},
+		{"{Module} ", "unknown placeholder {Module}"},
+		{"{} ", "unknown placeholder {}"},
+		{"{module ", `unclosed "{module "`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			if err := lg.SetPrefixFormat("{module}: "); err != nil {
+				t.Fatal(err)
+			}
+			err := lg.SetPrefixFormat(tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want %s", err, tt.err)
+			}
+			lg.Info("m")
+			if got := buf.String(); got != "TEST: m\n" {
+				t.Fatalf("got %q, want the previous layout kept", got)
+			}
+		})
+	}
+}
+
+func TestPrefixFormatReset(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetPrefixFormat("{module}: ")
+	lg.SetLevelTag(LevelInfo, "info ")
+	lg.Info("custom")
+	lg.SetPrefixFormat("")
+	lg.Info("default")
+	if got, want := buf.String(), "TEST: custom\n[TEST] info default\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
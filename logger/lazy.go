@@ -0,0 +1,59 @@
+package logger
+
+import "fmt"
+
+// lazyFunc defers a func() string argument until the message is written
+type lazyFunc func() string
+
+func (f lazyFunc) String() string { return f() }
+
+// formatted is an argument already formatted on the caller's goroutine. It
+// isn't a string kind so fmt.Sprint spaces it like the original value.
+type formatted struct{ s string }
+
+func (f formatted) String() string { return f.s }
+
+// lazyArgs reports whether v holds a func() string or a fmt.Stringer and
+// returns the arguments to format on the consumer. Anything else is
+// formatted right away so it can't change before the message is written.
+func lazyArgs(v []any) ([]any, bool) {
+	lazy := false
+	for _, a := range v {
+		switch a.(type) {
+		case func() string:
+			lazy = true
+		case error:
+		case fmt.Stringer:
+			lazy = true
+		}
+	}
+	if !lazy {
+		return nil, false
+	}
+
+	args := make([]any, len(v))
+	for i, a := range v {
+		switch t := a.(type) {
+		case string:
+			args[i] = t // strings change the spacing of fmt.Sprint
+		case func() string:
+			args[i] = lazyFunc(t)
+		case error:
+			args[i] = formatted{fmt.Sprint(t)}
+		case fmt.Stringer:
+			args[i] = t
+		default:
+			args[i] = formatted{fmt.Sprint(t)}
+		}
+	}
+	return args, true
+}
+
+// resolve formats the deferred arguments of m, once
+func (lg *core) resolve(m *logMessage) {
+	if m.args == nil {
+		return
+	}
+	m.msg = lg.truncate(fmt.Sprint(m.args...))
+	m.args = nil
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingStringer counts how often it is formatted
+type countingStringer struct{ n *atomic.Int32 }
+
+func (s countingStringer) String() string {
+	s.n.Add(1)
+	return "expensive"
+}
+
+func TestDisabledLevelsSkipFormatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		log   func(lg *Logger, s countingStringer)
+		calls int32 // times the argument is formatted at level Info
+	}{
+		{"Debug", func(lg *Logger, s countingStringer) { lg.Debug(s) }, 0},
+		{"Debugf", func(lg *Logger, s countingStringer) { lg.Debugf("%v", s) }, 0},
+		{"Trace", func(lg *Logger, s countingStringer) { lg.Trace(s) }, 0},
+		{"DebugFunc", func(lg *Logger, s countingStringer) { lg.DebugFunc(s.String) }, 0},
+		{"TraceFunc", func(lg *Logger, s countingStringer) { lg.TraceFunc(s.String) }, 0},
+		{"LogFunc disabled", func(lg *Logger, s countingStringer) { lg.LogFunc(LevelDebug, s.String) }, 0},
+		{"func argument", func(lg *Logger, s countingStringer) { lg.Debug(func() string { return s.String() }) }, 0},
+		{"Info", func(lg *Logger, s countingStringer) { lg.Info(s) }, 1},
+		{"LogFunc enabled", func(lg *Logger, s countingStringer) { lg.LogFunc(LevelWarn, s.String) }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+			s := countingStringer{new(atomic.Int32)}
+			tt.log(lg, s)
+			lg.Flush()
+
+			if got := s.n.Load(); got != tt.calls {
+				t.Fatalf("formatted %d times, want %d", got, tt.calls)
+			}
+			if strings.Contains(buf.String(), "expensive") != (tt.calls > 0) {
+				t.Fatalf("output = %q", buf)
+			}
+		})
+	}
+}
+
+func TestRaisedLevelKeepsQueuedMessages(t *testing.T) {
+	w := newBlockedWriter()
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+
+	lg.Info("first")
+	<-w.entered
+	lg.Info("queued")
+	lg.SetLevel(LevelError)
+	lg.Info("dropped")
+	close(w.release)
+	lg.Flush()
+
+	if got, want := w.String(), "[TEST] [I]   first\n[TEST] [I]   queued\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestLazyArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		level LogLevel // of the logger
+		log   func(lg *Logger, s countingStringer)
+		calls int32
+		want  string
+	}{
+		{"func", LevelInfo, func(lg *Logger, s countingStringer) { lg.Info(func() string { return s.String() }) }, 1, "[I]   expensive\n"},
+		{"stringer", LevelInfo, func(lg *Logger, s countingStringer) { lg.Warn("got ", s) }, 1, "[W] ? got expensive\n"},
+		{"spacing", LevelInfo, func(lg *Logger, s countingStringer) { lg.Info(1, 2, s, "x", 3) }, 1, "[I]   1 2 expensivex3\n"}, // spaced like fmt.Sprint of the originals,
+		{"error formatted eagerly", LevelInfo, func(lg *Logger, s countingStringer) { lg.Error(errors.New("boom"), s) }, 1, "<E> ! boom expensive\n"},
+		{"filtered", LevelError, func(lg *Logger, s countingStringer) { lg.Warn(s, func() string { return s.String() }) }, 0, ""},
+		{"two writers and JSON", LevelInfo, func(lg *Logger, s countingStringer) {
+			lg.AddWriter(JSONTo(io.Discard))
+			lg.AddWriter(io.Discard)
+			lg.Info(s)
+		}, 1, "[I]   expensive\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(tt.level))
+			lg.SetPrintModule(false)
+			s := countingStringer{new(atomic.Int32)}
+			tt.log(lg, s)
+			lg.Flush()
+
+			if got := s.n.Load(); got != tt.calls {
+				t.Fatalf("evaluated %d times, want %d", got, tt.calls)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazyArgumentsOnConsumer(t *testing.T) {
+	w := newBlockedWriter()
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+
+	lg.Info("first")
+	<-w.entered
+	s := countingStringer{new(atomic.Int32)}
+	data := []int{1, 2}
+	lg.Info(s, data) // data is formatted now, s once the message is written
+	data[0] = 9
+	if got := s.n.Load(); got != 0 {
+		t.Fatalf("evaluated %d times before the consumer got to the message", got)
+	}
+	close(w.release)
+	lg.Flush()
+
+	if got, want := w.String(), "[TEST] [I]   first\n[TEST] [I]   expensive [1 2]\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := s.n.Load(); got != 1 {
+		t.Fatalf("evaluated %d times, want 1", got)
+	}
+}
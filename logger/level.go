@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lowercase level names, also used by the machine readable formats
+var levelNames = map[LogLevel]string{
+	LevelDisabled: "disabled",
+	LevelTrace:    "trace",
+	LevelPrint:    "print",
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelWarn:     "warn",
+	LevelError:    "error",
+	LevelFatal:    "fatal",
+	LevelPanic:    "panic",
+}
+
+// String returns the lowercase name of the level
+func (l LogLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// ParseLevel returns the level for a case-insensitive name
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "disabled", "none", "off":
+		return LevelDisabled, nil
+	case "trace":
+		return LevelTrace, nil
+	case "print", "all":
+		return LevelPrint, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	case "panic":
+		return LevelPanic, nil
+	}
+	return LevelPrint, fmt.Errorf("unknown log level '%s'", s)
+}
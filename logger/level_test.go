@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLevelRoundTrip(t *testing.T) {
+	for level := range levelNames {
+		got, err := ParseLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", level.String(), got, err, level)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"debug", LevelDebug, false},
+		{"DEBUG", LevelDebug, false},
+		{" Info ", LevelInfo, false},
+		{"warning", LevelWarn, false},
+		{"Warn", LevelWarn, false},
+		{"error", LevelError, false},
+		{"fatal", LevelFatal, false},
+		{"off", LevelDisabled, false},
+		{"verbose", LevelPrint, true},
+		{"", LevelPrint, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("ParseLevel(%q) = %v, %v", tt.in, got, err)
+			}
+			if err != nil && err.Error() != "unknown log level '"+tt.in+"'" {
+				t.Fatalf("error = %q", err)
+			}
+		})
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	if got := LogLevel(42).String(); got != "LogLevel(42)" {
+		t.Fatalf("String of an unknown level = %q", got)
+	}
+}
+
+func TestLogLevelEnv(t *testing.T) {
+	tests := []struct {
+		env, value string
+		want       LogLevel
+	}{
+		{"LOG_LEVEL", "debug", LevelDebug},
+		{"LOG_LEVEL", "WARNING", LevelWarn},
+		{"LOGGER_LEVEL", "error", LevelError},
+		{"LOG_LEVEL", "bogus", LevelPrint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", "")
+			t.Setenv("LOGGER_LEVEL", "")
+			t.Setenv(tt.env, tt.value)
+			lg := NewLogger("TEST", WithWriters(&syncBuffer{}))
+			defer lg.Close()
+			if got := lg.GetLevel(); got != tt.want {
+				t.Fatalf("level = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []LogLevel
+		restore   []int // indexes into overrides, in call order
+		want      LogLevel
+	}{
+		{"restore", []LogLevel{LevelDebug}, []int{0}, LevelInfo},
+		{"not restored", []LogLevel{LevelDebug}, nil, LevelDebug},
+		{"nested", []LogLevel{LevelDebug, LevelTrace}, []int{1, 0}, LevelInfo},
+		{"inner only", []LogLevel{LevelDebug, LevelTrace}, []int{1}, LevelDebug},
+		{"restore twice", []LogLevel{LevelDebug, LevelTrace}, []int{1, 1}, LevelDebug},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, _ := newTestLogger(t, WithLevel(LevelInfo))
+			var restores []func()
+			for _, l := range tt.overrides {
+				restores = append(restores, lg.OverrideLevel(l))
+				if lg.GetLevel() != l {
+					t.Fatalf("level = %v during the override, want %v", lg.GetLevel(), l)
+				}
+			}
+			for _, i := range tt.restore {
+				restores[i]()
+			}
+			if got := lg.GetLevel(); got != tt.want {
+				t.Fatalf("level = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAtLevel(t *testing.T) {
+	lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+	lg.SetPrintModule(false)
+	verbose := lg.AtLevel(LevelDebug)
+	quiet := lg.AtLevel(LevelError)
+
+	var wg sync.WaitGroup
+	for _, l := range []*Logger{lg, verbose, quiet} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				l.Debug("debug")
+				l.Warn("warn")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "[D]   debug\n"); got != 100 {
+		t.Errorf("%d debug lines, want the 100 of the verbose logger", got)
+	}
+	if got := strings.Count(buf.String(), "[W] ? warn\n"); got != 200 {
+		t.Errorf("%d warnings, want 200 without the quiet logger", got)
+	}
+	if lg.GetLevel() != LevelInfo || verbose.GetLevel() != LevelDebug {
+		t.Fatalf("levels %v and %v", lg.GetLevel(), verbose.GetLevel())
+	}
+
+	lg.SetLevel(LevelWarn)
+	if verbose.GetLevel() != LevelDebug {
+		t.Fatal("SetLevel on the parent changed the AtLevel logger")
+	}
+}
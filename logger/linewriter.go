@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+)
+
+// LineWriter is an io.Writer logging every line written to it at a fixed
+// level, for libraries that want an io.Writer or *log.Logger
+type LineWriter struct {
+	lg    *Logger
+	level LogLevel
+	skip  int  // frames between the caller of interest and Write
+	strip bool // remove escape sequences, see CommandWriter
+
+	mu  sync.Mutex
+	buf []byte // partial line waiting for its newline
+}
+
+// WriterLevel returns a writer logging each written line at level
+func (lg *Logger) WriterLevel(level LogLevel) *LineWriter {
+	return &LineWriter{lg: lg, level: level}
+}
+
+// StdLogger returns a *log.Logger without prefix or flags writing at level
+func (lg *Logger) StdLogger(level LogLevel) *log.Logger {
+	w := lg.WriterLevel(level)
+	w.skip = 2 // log.Logger.Printf and log.Logger.output
+	return log.New(w, "", 0)
+}
+
+// Write logs every complete line, a trailing partial line is kept until
+// the next Write or Flush
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.skip+2, w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush logs the buffered partial line, if any
+func (w *LineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(w.skip+2, w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *LineWriter) emit(depth int, line []byte) {
+	s := strings.TrimSuffix(string(line), "\r")
+	if w.strip {
+		s = stripANSI(s)
+	}
+	w.lg.logDepth(depth, w.level, s)
+}
+
+// CaptureStdLog redirects the standard library's global logger into lg at
+// level. The date and time flags are cleared since lg adds its own
+// timestamp. The returned function restores the previous output and flags.
+func CaptureStdLog(lg *Logger, level LogLevel) func() {
+	out, flags := log.Writer(), log.Flags()
+	w := lg.WriterLevel(level)
+	w.skip = 2 // log.Printf and log.Logger.output
+
+	log.SetOutput(w)
+	log.SetFlags(flags &^ (log.Ldate | log.Ltime | log.Lmicroseconds))
+
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+		w.Flush()
+	}
+}
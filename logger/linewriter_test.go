@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriterLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  LogLevel
+		writes []string
+		flush  bool
+		want   string
+	}{
+		{"one line", LevelInfo, []string{"hello\n"}, false, "[I]   hello\n"},
+		{"several lines", LevelWarn, []string{"a\nb\n"}, false, "[W] ? a\n[W] ? b\n"},
+		{"split line", LevelInfo, []string{"hel", "lo\nwor", "ld\n"}, false, "[I]   hello\n[I]   world\n"},
+		{"partial kept", LevelInfo, []string{"done\npartial"}, false, "[I]   done\n"},
+		{"partial flushed", LevelInfo, []string{"done\npartial"}, true, "[I]   done\n[I]   partial\n"},
+		{"crlf", LevelError, []string{"win\r\n"}, false, "<E> ! win\n"},
+		{"empty line", LevelInfo, []string{"\n"}, false, "[I]   \n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			w := lg.WriterLevel(tt.level)
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write = %d, %v", n, err)
+				}
+			}
+			if tt.flush {
+				w.Flush()
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	std := lg.StdLogger(LevelWarn)
+	std.Printf("http: TLS handshake error from %s", "1.2.3.4")
+	std.Print("multi\nline")
+
+	want := "[TEST] [W] ? http: TLS handshake error from 1.2.3.4\n" +
+		"[TEST] [W] ? multi\n" +
+		"[TEST] [W] ? line\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestCaptureStdLog(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	log.SetFlags(log.LstdFlags)
+	restore := CaptureStdLog(lg, LevelWarn)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				log.Println("line", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	restore()
+	if log.Flags() != log.LstdFlags {
+		t.Fatalf("flags not restored: %d", log.Flags())
+	}
+	log.SetFlags(log.LstdFlags)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	var want []string
+	for g := 0; g < 4; g++ {
+		for i := 0; i < 25; i++ {
+			want = append(want, fmt.Sprintf("[TEST] [W] ? line %d %d", g, i))
+		}
+	}
+	sort.Strings(lines)
+	sort.Strings(want)
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("captured lines:\n%s", buf)
+	}
+}
@@ -1,20 +1,21 @@
 package logger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"log"
 	"os"
-	"regexp"
-	"strconv"
-	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ANSI colors
 type Color string
 
 const (
+	// Reset clears colors and style modifiers
 	Reset Color = "\033[0m"
 
 	// Regular colors
@@ -36,9 +37,26 @@ const (
 	BrightMagenta Color = "\033[95m"
 	BrightCyan    Color = "\033[96m"
 	BrightWhite   Color = "\033[97m"
+
+	// Background colors
+	BgBlack   Color = "\033[40m"
+	BgRed     Color = "\033[41m"
+	BgGreen   Color = "\033[42m"
+	BgYellow  Color = "\033[43m"
+	BgBlue    Color = "\033[44m"
+	BgMagenta Color = "\033[45m"
+	BgCyan    Color = "\033[46m"
+	BgWhite   Color = "\033[47m"
+	BgGrey    Color = "\033[100m"
+
+	// Style modifiers, combine them with a color using Combine
+	Bold      Color = "\033[1m"
+	Dim       Color = "\033[2m"
+	Italic    Color = "\033[3m"
+	Underline Color = "\033[4m"
 )
 
-// Highlight keywords
+// Default highlight keywords, matched as whole words and case-sensitive
 var highlights = map[string]Color{
 	"OK":    Green,
 	"ERROR": Red,
@@ -64,22 +82,107 @@ type LogLevel int
 type logMessage struct {
 	level LogLevel
 	msg   string
+	time  time.Time     // when it was logged, not when it is written
+	done  chan struct{} // closed by run() once the message is written
+	flush bool          // sentinel used by Flush, never written
+	ctrl  func()        // run in order by the consumer for flush messages
+
+	module string
+	color  Color
+	fields []Field
+	caller string   // file:line of the call site when reported
+	stack  []string // frames of the call site, see SetStackTraceLevel
+	err    error    // set by Err
+
+	internal bool   // produced by the logger itself, skips hooks
+	progress bool   // periodic progress line, terminals draw the bar instead
+	indent   int    // nesting depth of groups
+	style    Color  // applied to the message in colored output
+	framed   string // replaces msg in colored output, see Banner
+	args     []any  // formatted into msg by the consumer, see lazyArgs
 }
 
-// Logger wraps log.Logger and a channel for async logging
+// Logger renders messages to its writers from a channel for async logging
 type Logger struct {
-	l      *log.Logger
-	logCh  chan logMessage
-	done   chan struct{}
-	closed bool
-
-	printTime   bool
-	maxLogLevel LogLevel
-	sync        bool
-	colorOutput bool
+	*core
+	*levels
 
 	color  Color
 	module string
+	fields []Field // attached to every message, see WithFields
+	every  *limiter
+	group  *Group // innermost open group, see Logger.Group
+
+	derived bool // shares the core of another logger, see Clone
+}
+
+// core is the state shared between a Logger and the loggers derived from it
+type core struct {
+	wmu        sync.Mutex // serializes writes to the sinks, guards the slices
+	sinks      []sink
+	writers    []io.Writer
+	nextWriter WriterHandle
+	logCh      chan logMessage
+	done       chan struct{}
+
+	mu          sync.RWMutex // guards closed and sends on logCh
+	closed      bool
+	closeOnce   sync.Once
+	writersOnce sync.Once
+	hasConsumer bool         // false for loggers created in sync mode
+	consumer    atomic.Int64 // goroutine id of run
+
+	sync        atomic.Bool
+	colorMode   ColorMode
+	colorOutput bool // resolved colorMode across all writers
+
+	optsMu sync.Mutex // serializes updates of opts
+	opts   atomic.Pointer[renderOptions]
+
+	exitFunc func(code int)
+	exitCode int
+	repanic  atomic.Bool
+
+	batchBytes  int // 0 writes every line directly, see WithBatching
+	batchDelay  time.Duration
+	batchFailed atomic.Bool // a sink couldn't write its buffer
+
+	exitMu    sync.Mutex
+	exitHooks []func()
+	exitOnce  sync.Once
+
+	hooksMu     sync.RWMutex
+	hooks       []hookEntry
+	nextHook    HookID
+	hookTimeout atomic.Int64 // time.Duration, 0 runs hooks inline
+
+	filtersMu  sync.Mutex
+	filters    atomic.Pointer[filterSet]
+	nextFilter FilterID
+
+	now      func() time.Time // clock, replaceable in tests
+	sampler  atomic.Pointer[sampler]
+	collapse atomic.Pointer[collapser]
+	ring     atomic.Pointer[ringBuffer]
+
+	progress *Progress // line redrawn on terminals, guarded by wmu
+
+	keywords atomic.Pointer[keywordSet] // nil uses the package keywords
+
+	onceMu   sync.Mutex
+	onceKeys map[string]struct{}
+
+	reportCaller atomic.Bool
+	callerSkip   atomic.Int32
+	stackLevel   atomic.Int32 // LevelDisabled turns stack traces off
+	stackDepth   atomic.Int32
+
+	backpressure atomic.Int32 // Backpressure
+	dropped      atomic.Uint64
+	backlog      atomic.Pointer[backlogWatch]
+	unreported   atomic.Uint64 // drops not yet announced in the output
+
+	maxMessageSize atomic.Int64 // 0 is unlimited
 }
 
 const (
@@ -90,269 +193,564 @@ const (
 	LevelWarn
 	LevelError
 	LevelFatal
-)
 
-var colorRegex *regexp.Regexp
+	// LevelTrace sits below every other level, it has an explicit value so
+	// the numbers of the levels above stay unchanged
+	LevelTrace LogLevel = -2
 
-func init() {
-	words := make([]string, 0, len(highlights))
-	for w := range highlights {
-		words = append(words, regexp.QuoteMeta(w))
-	}
-
-	// Add regex pattern for numbers (integers, floats, hex)
-	numberPattern := `\b\d+(\.\d+)?\b|0x[0-9A-Fa-f]+`
-
-	// Combine words and number pattern
-	allPatterns := append(words, numberPattern)
-
-	colorRegex = regexp.MustCompile(strings.Join(allPatterns, "|"))
-}
+	// LevelPanic is used by Panic and ranks above Fatal
+	LevelPanic LogLevel = 6
+)
 
 // New creates a new async logger
+//
+// Deprecated: use NewLogger with WithColor and WithWriters, which can be
+// extended without breaking callers.
 func New(module string, color Color, writers ...io.Writer) *Logger {
-	// Defaults
-	sync := false
-	printTime := true
-	level := LevelPrint
-	colorOutput := true
-	fast := false
-
-	levelStr := os.Getenv("LOGGER_LEVEL")
-	if levelStr != "" {
-		switch levelStr {
-		case "disabled", "none", "off":
-			level = LevelDisabled
-		case "debug":
-			level = LevelDebug
-		case "info":
-			level = LevelInfo
-		case "warn":
-			level = LevelWarn
-		case "error":
-			level = LevelError
-		case "fatal":
-			level = LevelFatal
-		case "all":
-			level = LevelPrint
-		}
-	}
-
-	sync, err := strconv.ParseBool(os.Getenv("LOGGER_SYNC"))
-	if err != nil {
-		sync = false
-	}
-	printTime, err = strconv.ParseBool(os.Getenv("LOGGER_TIME"))
-	if err != nil {
-		printTime = true
-	}
-	colorOutput, err = strconv.ParseBool(os.Getenv("LOGGER_COLORS"))
-	if err != nil {
-		colorOutput = true
-	}
-	fast, err = strconv.ParseBool(os.Getenv("LOGGER_FAST"))
-	if err != nil {
-		fast = false
-	}
+	return NewLogger(module, WithColor(color), WithWriters(writers...))
+}
 
-	if fast {
-		level = LevelPrint
-		sync = true
-		printTime = false
-		colorOutput = false
+// NewLogger creates a logger configured by the environment and then by
+// opts. The consumer goroutine starts after every option was applied, so no
+// message is ever handled with partial settings.
+func NewLogger(module string, opts ...Option) *Logger {
+	registerModule(module)
+	c := envConfig()
+	for _, o := range opts {
+		o(c)
 	}
-
-	out := io.MultiWriter(os.Stdout)
-	if len(writers) > 0 {
-		out = io.MultiWriter(writers...)
+	if l, ok := moduleLevel(module); ok {
+		c.level = l
 	}
 
-	prefix := fmt.Sprintf("%s[%s]%s ", color, module, Grey)
-	if !colorOutput {
-		prefix = fmt.Sprintf("[%s] ", module)
-	}
-	lg := &Logger{
-		l:           log.New(out, prefix, log.LstdFlags),
-		logCh:       make(chan logMessage, 100), // buffered channel
-		done:        make(chan struct{}),
-		maxLogLevel: level,
-		printTime:   printTime,
-		sync:        sync,
-		colorOutput: colorOutput,
-		color:       color,
-		module:      module,
+	writers := c.writers
+	if len(writers) == 0 {
+		writers = []io.Writer{os.Stdout}
 	}
+	sinks, writers := newSinks(writers)
+
+	lg := &Logger{core: &core{
+		sinks:     sinks,
+		writers:   writers,
+		logCh:     make(chan logMessage, c.bufferSize), // buffered channel
+		done:      make(chan struct{}),
+		colorMode: c.colorMode,
+		exitFunc:  os.Exit,
+		exitCode:  1,
+		now:       time.Now,
+	}, levels: newLevels(c.level), color: c.color, module: module, fields: c.fields()}
+
+	lg.sync.Store(c.sync)
+	lg.opts.Store(&renderOptions{
+		format:      c.format,
+		printTime:   c.printTime,
+		printModule: true,
+		highlight:   true,
+		sanitize:    true,
+		theme:       DefaultTheme(),
+		timeFormat:  c.timeFormat,
+	})
+	lg.batchBytes, lg.batchDelay = c.batchBytes, c.batchDelay
+	lg.bufferSinks()
+	lg.stackLevel.Store(int32(LevelDisabled))
+	lg.stackDepth.Store(32)
+	lg.maxMessageSize.Store(defaultMaxMessageSize)
+	lg.resolveColors()
 
 	// start logger goroutine
-	if !sync {
+	if !c.sync {
+		lg.hasConsumer = true
 		go lg.run()
 	} else {
 		lg.closed = true
 	}
 
-	if !lg.printTime {
-		lg.l.SetFlags(0)
-	} else {
-		prefix := fmt.Sprintf("%s[%s]%s ", lg.color, lg.module, Grey)
-		lg.l.SetPrefix(prefix)
-		lg.l.SetFlags(log.LstdFlags)
-	}
-
 	return lg
 }
 
-func (lg *Logger) SetLevel(level LogLevel) {
-	lg.maxLogLevel = level
+// enabled reports whether a message at level passes the current level.
+// The check happens on the producer side, so disabled calls return before
+// their arguments are formatted. Fatal always passes, it has to reach emit
+// to exit even when the logger is disabled or set above Fatal.
+func (lg *Logger) enabled(level LogLevel) bool {
+	if level == LevelFatal {
+		return true
+	}
+	max := lg.GetLevel()
+	return max != LevelDisabled && level >= max
 }
 
+// Enabled reports whether messages at level are currently written, for
+// guarding expensive preparation of log arguments
+func (lg *Logger) Enabled(level LogLevel) bool {
+	return lg.enabled(level)
+}
+
+// SetSync toggles writing on the caller's goroutine instead of queueing,
+// messages are then visible in the writers as soon as the call returns.
+// Recommended for tests and short lived CLIs. A logger created in sync mode
+// has no consumer goroutine, so it keeps writing directly either way.
 func (lg *Logger) SetSync(sync bool) {
-	lg.sync = sync
+	if sync {
+		// Keep ordering with what is already queued
+		lg.Flush()
+	}
+	lg.sync.Store(sync)
 }
 
-func AddHighlight(word string, color Color) {
-	highlights[word] = color
+// SetExitFunc replaces os.Exit as the function called after a Fatal message
+func (lg *Logger) SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+	lg.exitFunc = fn
 }
 
-func (lg *Logger) SetPrintTime(print bool) {
-	lg.printTime = print
+// SetFatalExitCode sets the exit code used by Fatal, 1 by default
+func (lg *Logger) SetFatalExitCode(code int) {
+	lg.exitCode = code
 }
 
 // run listens on the channel and prints messages
-func (lg *Logger) run() {
-	for m := range lg.logCh {
-		if m.level < lg.maxLogLevel {
-			continue
+func (lg *core) run() {
+	lg.consumer.Store(goid())
+	tick, stop := lg.batchTicker()
+	defer stop()
+	for {
+		select {
+		case m, ok := <-lg.logCh:
+			if !ok {
+				lg.flushPending()
+				lg.writersOnce.Do(lg.closeWriters)
+				close(lg.done)
+				return
+			}
+			lg.printer(m)
+			if m.done != nil {
+				close(m.done)
+			}
+			lg.reportDropped(m)
+			lg.reportBatchErrors(m)
+			lg.rearmBacklog()
+		case <-tick:
+			lg.wmu.Lock()
+			lg.flushBatch()
+			lg.wmu.Unlock()
 		}
-		lg.printer(m)
 	}
-	close(lg.done)
 }
 
-func (lg *Logger) printer(m logMessage) {
-	if m.level < lg.maxLogLevel {
+func (lg *core) printer(m logMessage) {
+	if m.flush {
+		if m.ctrl != nil {
+			m.ctrl()
+		}
+		if lg.batching() {
+			lg.wmu.Lock()
+			lg.flushBatch()
+			lg.wmu.Unlock()
+		}
 		return
 	}
-
-	msg := m.msg
-	if lg.colorOutput {
-		msg = colorString(msg) // color the content
+	lg.resolve(&m)
+	if !m.internal && lg.filtered(m) {
+		return
 	}
+	redact(&m)
+	if !m.internal {
+		if lg.collapsed(m) || !lg.sample(m) {
+			return
+		}
+		lg.runHooks(m)
+	}
+	boxOnly := m.msg == "" && m.framed != "" // frame line of a banner
+
+	// Render each variant at most once and send it to the matching sinks
+	var colored, plain, jsonLine, logfmtLine *bytes.Buffer
+	defer func() {
+		for _, b := range [...]*bytes.Buffer{colored, plain, jsonLine, logfmtLine} {
+			if b != nil {
+				putBuffer(b)
+			}
+		}
+	}()
+	now := m.time
+	if now.IsZero() {
+		now = lg.now()
+	}
+	format := lg.opts.Load().format
 
-	switch m.level {
-	case LevelInfo:
-		if lg.colorOutput {
-			lg.l.Printf("%s[I]%s   %s", Blue, Reset, msg)
-		} else {
-			lg.l.Printf("[I]   %s", msg)
+	lg.wmu.Lock()
+	defer lg.wmu.Unlock()
+	for _, s := range lg.sinks {
+		if !s.accepts(m.level) {
+			continue
 		}
-	case LevelWarn:
-		if lg.colorOutput {
-			lg.l.Printf("%s[W] ? %s%s", Yellow, Reset, msg)
-		} else {
-			lg.l.Printf("[W] ? %s", msg)
+		switch s.formatOr(format) {
+		case FormatJSON:
+			if !boxOnly {
+				if jsonLine == nil {
+					jsonLine = getBuffer()
+					lg.writeJSON(jsonLine, m, now)
+				}
+				s.write(m.level, jsonLine.Bytes())
+			}
+			continue
+		case FormatLogfmt:
+			if !boxOnly {
+				if logfmtLine == nil {
+					logfmtLine = getBuffer()
+					lg.writeLogfmt(logfmtLine, m, now)
+				}
+				s.write(m.level, logfmtLine.Bytes())
+			}
+			continue
 		}
-	case LevelError:
-		if lg.colorOutput {
-			lg.l.Printf("%s<E> ! %s%s", Red, Reset, msg)
-		} else {
-			lg.l.Printf("<E> ! %s", msg)
+
+		if m.progress && s.tty || boxOnly && !s.color {
+			continue
 		}
-	case LevelDebug:
-		if lg.colorOutput {
-			lg.l.Printf("%s[D]%s   %s", Grey, Reset, msg)
-		} else {
-			lg.l.Printf("[D]   %s", msg)
+		if lg.progress != nil && s.tty {
+			// the message replaces the bar, which is drawn again below it
+			s.write(m.level, []byte(clearLine))
+			defer lg.progress.draw(s)
 		}
-	case LevelFatal:
-		if lg.colorOutput {
-			lg.l.Printf("%s<F>!!! %s%s", Red, Reset, msg)
+		if s.color {
+			if colored == nil {
+				colored = getBuffer()
+				lg.render(colored, m, now, true)
+			}
+			s.write(m.level, colored.Bytes())
 		} else {
-			lg.l.Printf("<F>!!! %s", msg)
+			if plain == nil {
+				plain = getBuffer()
+				lg.render(plain, m, now, false)
+			}
+			s.write(m.level, plain.Bytes())
 		}
-		os.Exit(1)
-	default:
-		lg.l.Printf("%s", msg)
 	}
 }
 
-var msgPool = sync.Pool{
-	New: func() any {
-		return new(logMessage)
-	},
+// Log pushes a message to the log channel. Arguments that are a func()
+// string or a fmt.Stringer are only evaluated once the message passed the
+// level filter, on the logger's goroutine, so they must be safe to call
+// from there. This applies to Info, Debug and the other leveled methods
+// too.
+func (lg *Logger) Log(level LogLevel, v ...any) {
+	lg.logDepth(1, level, v...)
 }
 
-// Log pushes a message to the log channel
-func (lg *Logger) Log(level LogLevel, v ...any) {
-	if level < lg.maxLogLevel {
+// logDepth logs v at level, depth is the number of frames between the
+// call site and logDepth, used for caller reporting
+func (lg *Logger) logDepth(depth int, level LogLevel, v ...any) {
+	ring := lg.ring.Load()
+	if ring == nil && !lg.enabled(level) {
 		return
 	}
-	m := msgPool.Get().(*logMessage)
-	m.level = level
-	m.msg = colorString(fmt.Sprint(v...))
+	if args, ok := lazyArgs(v); ok && ring == nil {
+		lg.outputLazy(depth+1, level, args)
+		return
+	}
+	lg.output(depth+1, level, sprint(v))
+}
 
-	if !lg.colorOutput {
-		m.msg = fmt.Sprint(v...)
+// sprint is fmt.Sprint without the allocation for a single string
+func sprint(v []any) string {
+	if len(v) == 1 {
+		if s, ok := v[0].(string); ok {
+			return s
+		}
 	}
-	if lg.sync {
-		lg.printer(*m)
-	} else {
-		lg.logCh <- *m
+	return fmt.Sprint(v...)
+}
+
+// logfDepth is logDepth for formatted messages
+func (lg *Logger) logfDepth(depth int, level LogLevel, format string, v ...any) {
+	ring := lg.ring.Load()
+	if ring == nil && !lg.enabled(level) {
+		return
+	}
+	lg.output(depth+1, level, fmt.Sprintf(format, v...))
+}
+
+// output hands off the message when the level is enabled, below it the
+// message only goes to the ring buffer
+func (lg *Logger) output(depth int, level LogLevel, msg string) {
+	m := lg.message(level, msg)
+	if !lg.enabled(level) {
+		lg.record(m)
+		return
+	}
+	lg.emitAt(depth+1, m)
+}
+
+// outputLazy hands off a message whose arguments are formatted by the
+// consumer
+func (lg *Logger) outputLazy(depth int, level LogLevel, args []any) {
+	m := lg.message(level, "")
+	m.args = args
+	lg.emitAt(depth+1, m)
+}
+
+// emitAt adds the caller and stack of the frame depth levels up and emits
+// m
+func (lg *Logger) emitAt(depth int, m logMessage) {
+	skip := depth + 1 + int(lg.callerSkip.Load())
+	if lg.reportCaller.Load() {
+		m.caller = caller(skip)
+	}
+	if sl := LogLevel(lg.stackLevel.Load()); sl != LevelDisabled && m.level >= sl {
+		m.stack = stack(skip, int(lg.stackDepth.Load()))
+	}
+	lg.emit(m)
+}
+
+// message returns a message carrying the identity and fields of lg
+func (lg *Logger) message(level LogLevel, msg string) logMessage {
+	return logMessage{
+		level:  level,
+		msg:    lg.truncate(msg),
+		time:   lg.now(),
+		module: lg.module,
+		color:  lg.color,
+		fields: lg.fields,
+		indent: lg.group.depth(),
+	}
+}
+
+// emit dispatches m, for Fatal it waits until everything queued before it
+// has been written and then exits
+func (lg *Logger) emit(m logMessage) {
+	fatal := m.level == LevelFatal
+	if lg.every != nil && !fatal && !lg.every.allow(lg.now()) {
+		lg.record(m)
+		return
+	}
+	if m.level >= LevelError {
+		lg.group.fail()
+	}
+	lg.dispatch(m, fatal)
+
+	if fatal {
+		lg.syncWriters()
+		lg.runExitHooks()
+		lg.exitFunc(lg.exitCode)
+	}
+}
+
+// dispatch writes m directly in sync mode or once the logger is closed,
+// otherwise queues it. With wait set it blocks until m has been written,
+// on the consumer itself (from a hook) m is written inline instead of
+// waiting for a message that would never be taken.
+func (lg *core) dispatch(m logMessage, wait bool) {
+	lg.record(m)
+	if lg.sync.Load() || wait && lg.hasConsumer && lg.consumer.Load() == goid() {
+		lg.printDirect(m)
+		return
+	}
+
+	lg.mu.RLock()
+	if lg.closed {
+		// Consumer is gone, write directly instead of panicking on send
+		lg.mu.RUnlock()
+		lg.printDirect(m)
+		return
+	}
+	if wait {
+		m.done = make(chan struct{})
+	}
+	if wait || !lg.trySend(m) {
+		lg.logCh <- m
+	}
+	if !m.flush {
+		lg.checkBacklog() // Flush and control calls aren't a backlog
+	}
+	lg.mu.RUnlock()
+
+	if wait {
+		<-m.done
+	}
+}
+
+// printDirect writes m on the caller's goroutine, there is no ticker to
+// write out batched lines later
+func (lg *core) printDirect(m logMessage) {
+	lg.printer(m)
+	if !lg.batching() {
+		return
+	}
+	lg.wmu.Lock()
+	lg.flushBatch()
+	lg.wmu.Unlock()
+	if lg.batchFailed.Load() {
+		// write the report of the failure right away too
+		lg.reportBatchErrors(m)
+		lg.wmu.Lock()
+		lg.flushBatch()
+		lg.wmu.Unlock()
+	}
+}
+
+// Flush blocks until every message queued before the call has been written
+func (lg *core) Flush() {
+	lg.dispatch(logMessage{flush: true}, true)
+}
+
+// flushPending writes the summaries held back by collapsing and sampling
+func (lg *core) flushPending() {
+	if c := lg.collapse.Load(); c != nil {
+		c.mu.Lock()
+		lg.flushCollapsed(c, true)
+		c.mu.Unlock()
+	}
+	if s := lg.sampler.Load(); s != nil {
+		lg.flushSamples(s, true)
+	}
+}
+
+// syncWriters flushes writers that buffer, e.g. *os.File
+func (lg *core) syncWriters() {
+	lg.wmu.Lock()
+	defer lg.wmu.Unlock()
+	lg.flushBatch()
+	for _, w := range lg.writers {
+		if s, ok := w.(interface{ Sync() error }); ok {
+			s.Sync()
+		}
 	}
-	msgPool.Put(m)
 }
 
 // Info pushes a message to the log channel
 func (lg *Logger) Info(v ...any) {
-	lg.Log(LevelInfo, v...)
+	lg.logDepth(1, LevelInfo, v...)
 }
 
 // Warn pushes a message to the log channel
 func (lg *Logger) Warn(v ...any) {
-	lg.Log(LevelWarn, v...)
+	lg.logDepth(1, LevelWarn, v...)
 }
 
 // Error pushes a message to the log channel
 func (lg *Logger) Error(v ...any) {
-	lg.Log(LevelError, v...)
+	lg.logDepth(1, LevelError, v...)
+}
+
+func (lg *Logger) Trace(v ...any) {
+	lg.logDepth(1, LevelTrace, v...)
 }
 
 func (lg *Logger) Debug(v ...any) {
-	lg.Log(LevelDebug, v...)
+	lg.logDepth(1, LevelDebug, v...)
+}
+
+// LogFunc logs the result of fn, which is only called when level is
+// enabled
+func (lg *Logger) LogFunc(level LogLevel, fn func() string) {
+	lg.logFuncDepth(1, level, fn)
+}
+
+// DebugFunc is LogFunc at Debug
+func (lg *Logger) DebugFunc(fn func() string) {
+	lg.logFuncDepth(1, LevelDebug, fn)
+}
+
+// TraceFunc is LogFunc at Trace
+func (lg *Logger) TraceFunc(fn func() string) {
+	lg.logFuncDepth(1, LevelTrace, fn)
+}
+
+func (lg *Logger) logFuncDepth(depth int, level LogLevel, fn func() string) {
+	ring := lg.ring.Load()
+	if ring == nil && !lg.enabled(level) {
+		return
+	}
+	lg.output(depth+1, level, fn())
 }
 
 // Print pushes a colored message to the log channel
 func (lg *Logger) Print(v ...any) {
-	lg.Log(LevelPrint, v...)
+	lg.logDepth(1, LevelPrint, v...)
 }
 
 // Fatal pushes a message to the log channel and exits
 func (lg *Logger) Fatal(v ...any) {
-	lg.Log(LevelFatal, v...)
+	lg.logDepth(1, LevelFatal, v...)
 }
 
-func Hyperlink(url string, v ...any) string {
-	return fmt.Sprintf("\033]8;;%s\033\\%s\033]8;;\033\\", url, fmt.Sprint(v...))
+// Logf formats the message and pushes it to the log channel
+func (lg *Logger) Logf(level LogLevel, format string, v ...any) {
+	lg.logfDepth(1, level, format, v...)
 }
 
-// Close the logger (flushes remaining messages)
-func (lg *Logger) Close() {
-	if !lg.closed {
-		close(lg.logCh)
-		<-lg.done
-		lg.closed = true
-	}
+// Infof formats and pushes a message to the log channel
+func (lg *Logger) Infof(format string, v ...any) {
+	lg.logfDepth(1, LevelInfo, format, v...)
 }
 
-func ColorString(c Color, s ...any) string {
-	return fmt.Sprintf("%s%s%s", c, fmt.Sprint(s...), Reset)
+// Warnf formats and pushes a message to the log channel
+func (lg *Logger) Warnf(format string, v ...any) {
+	lg.logfDepth(1, LevelWarn, format, v...)
+}
+
+// Errorf formats and pushes a message to the log channel
+func (lg *Logger) Errorf(format string, v ...any) {
+	lg.logfDepth(1, LevelError, format, v...)
+}
+
+func (lg *Logger) Tracef(format string, v ...any) {
+	lg.logfDepth(1, LevelTrace, format, v...)
+}
+
+func (lg *Logger) Debugf(format string, v ...any) {
+	lg.logfDepth(1, LevelDebug, format, v...)
+}
+
+// Printf formats and pushes a colored message to the log channel
+func (lg *Logger) Printf(format string, v ...any) {
+	lg.logfDepth(1, LevelPrint, format, v...)
+}
+
+// Fatalf formats and pushes a message to the log channel and exits
+func (lg *Logger) Fatalf(format string, v ...any) {
+	lg.logfDepth(1, LevelFatal, format, v...)
 }
 
-// colorString replaces keywords with colored versions
-func colorString(s string) string {
-	return colorRegex.ReplaceAllStringFunc(s, func(match string) string {
-		if color, ok := highlights[match]; ok {
-			return string(color) + match + string(Reset)
+// close stops the consumer once it wrote the queued messages and closes
+// the writers, it gives up waiting when ctx is done
+func (lg *core) close(ctx context.Context) error {
+	lg.closeOnce.Do(func() {
+		lg.mu.Lock()
+		if !lg.closed {
+			close(lg.logCh)
+			lg.closed = true
 		}
-		return string(Cyan) + match + string(Reset) // fallback for numbers
+		lg.mu.Unlock()
 	})
+
+	switch {
+	case !lg.hasConsumer:
+		// Everything was written directly
+		lg.writersOnce.Do(func() {
+			lg.flushPending()
+			lg.closeWriters()
+		})
+	case lg.consumer.Load() == goid():
+		// Called from a hook, run closes the writers once it is done
+	default:
+		select {
+		case <-lg.done:
+			return nil // already drained, whatever the state of ctx
+		default:
+		}
+		select {
+		case <-lg.done:
+		case <-ctx.Done():
+			return fmt.Errorf("couldn't close logger, abandoned %d queued messages: %w", len(lg.logCh), ctx.Err())
+		}
+	}
+	return nil
+}
+
+func ColorString(c Color, s ...any) string {
+	return fmt.Sprintf("%s%s%s", c, fmt.Sprint(s...), Reset)
 }
@@ -0,0 +1,472 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for the consumer and the test to use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newTestLogger returns a synchronous, colorless logger without timestamps
+// writing to the returned buffer
+func newTestLogger(t *testing.T, opts ...Option) (*Logger, *syncBuffer) {
+	t.Helper()
+
+	buf := &syncBuffer{}
+	opts = append([]Option{
+		WithWriters(buf),
+		WithSync(true),
+		WithNoColor(),
+		WithPrintTime(false),
+	}, opts...)
+	lg := NewLogger("TEST", opts...)
+	t.Cleanup(lg.Close)
+	return lg, buf
+}
+
+func TestFatalExitsAtEveryLevel(t *testing.T) {
+	calls := map[string]func(lg *Logger){
+		"Fatal":  func(lg *Logger) { lg.Fatal("boom") },
+		"Fatalf": func(lg *Logger) { lg.Fatalf("%s", "boom") },
+		"Fatalw": func(lg *Logger) { lg.Fatalw("boom", "k", 1) },
+		"Log":    func(lg *Logger) { lg.Log(LevelFatal, "boom") },
+	}
+	tests := []struct {
+		name  string
+		level LogLevel
+	}{
+		{"print", LevelPrint},
+		{"error", LevelError},
+		{"disabled", LevelDisabled},
+		{"above fatal", LevelPanic},
+	}
+	for _, tt := range tests {
+		for name, call := range calls {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lg, _ := newTestLogger(t, WithLevel(tt.level))
+				code := -1
+				lg.SetExitFunc(func(c int) { code = c })
+				lg.SetFatalExitCode(3)
+
+				call(lg)
+				if code != 3 {
+					t.Fatalf("exit code = %d, want 3", code)
+				}
+			})
+		}
+	}
+}
+
+func TestFatalWritesQueuedMessagesFirst(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+	var out string
+	lg.SetExitFunc(func(int) { out = buf.String() })
+
+	lg.Info("first")
+	lg.Fatal("last")
+	if !strings.Contains(out, "first") || !strings.Contains(out, "last") {
+		t.Fatalf("output at exit = %q, want both messages", out)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		level, msg LogLevel
+		want       bool
+	}{
+		{LevelInfo, LevelDebug, false},
+		{LevelInfo, LevelInfo, true},
+		{LevelInfo, LevelError, true},
+		{LevelTrace, LevelTrace, true},
+		{LevelDebug, LevelTrace, false},
+		{LevelDisabled, LevelError, false},
+		{LevelDisabled, LevelFatal, true},
+		{LevelPanic, LevelFatal, true},
+	}
+	for _, tt := range tests {
+		lg, _ := newTestLogger(t, WithLevel(tt.level))
+		if got := lg.Enabled(tt.msg); got != tt.want {
+			t.Errorf("level %v: Enabled(%v) = %v, want %v", tt.level, tt.msg, got, tt.want)
+		}
+	}
+}
+
+// Messages are never used as format strings
+func TestMessageVerbs(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{"Info", func(lg *Logger) { lg.Info("progress 50%") }, "progress 50%"},
+		{"Info verbs", func(lg *Logger) { lg.Info("%s %d %!") }, "%s %d %!"},
+		{"Info args", func(lg *Logger) { lg.Info("at ", 100, "%") }, "at 100%"},
+		{"Print", func(lg *Logger) { lg.Print("100% done") }, "100% done"},
+		{"Infof arg", func(lg *Logger) { lg.Infof("%s", "50% off") }, "50% off"},
+		{"Infow", func(lg *Logger) { lg.Infow("50%", "k", "%v") }, "50% k=%v"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			tt.log(lg)
+			got := buf.String()
+			if !strings.HasSuffix(got, tt.want+"\n") || strings.Contains(got, "%!") != strings.Contains(tt.want, "%!") {
+				t.Fatalf("got %q, want it to end in %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormattedMethods(t *testing.T) {
+	err := fmt.Errorf("open: %w", os.ErrNotExist)
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string // empty when filtered
+	}{
+		{"Infof", func(lg *Logger) { lg.Infof("user %s from %v", "ann", []int{1, 2}) }, "[I]   user ann from [1 2]\n"},
+		{"Warnf", func(lg *Logger) { lg.Warnf("%d retries, %05.1f%%", 3, 2.5) }, "[W] ? 3 retries, 002.5%\n"},
+		{"Errorf wrapped", func(lg *Logger) { lg.Errorf("failed: %v", err) }, "<E> ! failed: open: file does not exist\n"},
+		{"Debugf filtered", func(lg *Logger) { lg.Debugf("%d", 1) }, ""},
+		{"Tracef filtered", func(lg *Logger) { lg.Tracef("%d", 1) }, ""},
+		{"Printf filtered", func(lg *Logger) { lg.Printf("%s done", "GET") }, ""},
+		{"Logf", func(lg *Logger) { lg.Logf(LevelWarn, "%q", "x") }, "[W] ? \"x\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+			lg.SetPrintModule(false)
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintfHighlighting(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.Printf("%s /index", "GET")
+	if got := buf.String(); !strings.Contains(got, string(Green)+"GET") {
+		t.Fatalf("keyword isn't highlighted: %q", got)
+	}
+}
+
+// slowWriter takes a while for every write, like a congested pipe
+type slowWriter struct {
+	syncBuffer
+	delay time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return w.syncBuffer.Write(p)
+}
+
+func TestFatalDrainsSlowWriter(t *testing.T) {
+	for _, sync := range []bool{false, true} {
+		t.Run(fmt.Sprint("sync=", sync), func(t *testing.T) {
+			w := &slowWriter{delay: 2 * time.Millisecond}
+			lg := NewLogger("TEST", WithWriters(w), WithSync(sync), WithNoColor(), WithPrintTime(false))
+			defer lg.Close()
+			var out string
+			calls := 0
+			lg.SetExitFunc(func(int) { calls++; out = w.String() })
+
+			var want strings.Builder
+			for i := 0; i < 20; i++ {
+				lg.Errorf("context %d", i)
+				fmt.Fprintf(&want, "[TEST] <E> ! context %d\n", i)
+			}
+			lg.Fatal("boom")
+			want.WriteString("[TEST] <F>!!! boom\n")
+
+			if calls != 1 {
+				t.Fatalf("exit called %d times", calls)
+			}
+			if out != want.String() {
+				t.Fatalf("output at exit:\n%s\nwant:\n%s", out, want.String())
+			}
+		})
+	}
+}
+
+func TestExitFunc(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  int // passed to SetFatalExitCode when not 0
+		fatal func(lg *Logger)
+		want  int
+	}{
+		{"default code", 0, func(lg *Logger) { lg.Fatal("boom") }, 1},
+		{"custom code", 2, func(lg *Logger) { lg.Fatal("boom") }, 2},
+		{"Fatalf", 2, func(lg *Logger) { lg.Fatalf("%s", "boom") }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			if tt.code != 0 {
+				lg.SetFatalExitCode(tt.code)
+			}
+			var codes []int
+			var out string
+			lg.SetExitFunc(func(code int) {
+				codes = append(codes, code)
+				out = buf.String()
+			})
+
+			tt.fatal(lg)
+			if len(codes) != 1 || codes[0] != tt.want {
+				t.Fatalf("exit calls = %v, want [%d]", codes, tt.want)
+			}
+			if out != "[TEST] <F>!!! boom\n" {
+				t.Fatalf("output when exiting = %q", out)
+			}
+		})
+	}
+}
+
+func TestLogAfterClose(t *testing.T) {
+	calls := map[string]func(lg *Logger){
+		"Info":   func(lg *Logger) { lg.Info("late") },
+		"Errorf": func(lg *Logger) { lg.Errorf("%s", "late") },
+		"Print":  func(lg *Logger) { lg.Print("late") },
+		"Infow":  func(lg *Logger) { lg.Infow("late") },
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+			lg.Close()
+			lg.Close()
+
+			call(lg)
+			lg.Flush()
+			if !strings.Contains(buf.String(), "late\n") {
+				t.Fatalf("message logged after Close is missing: %q", buf.String())
+			}
+		})
+	}
+}
+
+// Run with -race, logging concurrently with Close must neither panic nor race
+func TestLogConcurrentWithClose(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				lg.Infof("line %d", j)
+				if j == 100 {
+					lg.Close()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "\n"); got != 8*200 {
+		t.Fatalf("%d lines written, want %d", got, 8*200)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		debug bool // whether Debug is written
+		warn  bool
+	}{
+		{LevelTrace, true, true},
+		{LevelDebug, true, true},
+		{LevelWarn, false, true},
+		{LevelDisabled, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false), WithLevel(LevelError))
+			defer lg.Close()
+
+			lg.Debug("before")
+			lg.SetLevel(tt.level)
+			if got := lg.GetLevel(); got != tt.level {
+				t.Fatalf("GetLevel = %v", got)
+			}
+			lg.Debug("debug")
+			lg.Warn("warn")
+			lg.Flush()
+
+			out := buf.String()
+			if strings.Contains(out, "before") {
+				t.Fatal("message queued before SetLevel used the new level")
+			}
+			if strings.Contains(out, "debug") != tt.debug || strings.Contains(out, "warn") != tt.warn {
+				t.Fatalf("output = %q", out)
+			}
+		})
+	}
+}
+
+func TestSetLevelConcurrent(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			lg.SetLevel(LogLevel(i % int(LevelFatal+1)))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			lg.Info("x")
+			_ = lg.GetLevel()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestFlush(t *testing.T) {
+	w := &slowWriter{delay: time.Millisecond}
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+
+	for i := 0; i < 30; i++ {
+		lg.Info("queued")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.Flush()
+			if got := strings.Count(w.String(), "queued"); got < 30 {
+				t.Errorf("Flush returned after %d of 30 messages", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lg.Close()
+	done := make(chan struct{})
+	go func() {
+		lg.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Flush after Close blocked")
+	}
+}
+
+func TestSyncMode(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{"Info", func(lg *Logger) { lg.Info("now") }, "[TEST] [I]   now\n"},
+		{"Warnf", func(lg *Logger) { lg.Warnf("%d", 5) }, "[TEST] [W] ? 5\n"},
+		{"filtered", func(lg *Logger) { lg.Debug("hidden") }, ""},
+		{"fields", func(lg *Logger) { lg.WithField("k", "v").Error("e") }, "[TEST] <E> ! e k=v\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("right after the call got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSyncKeepsOrder(t *testing.T) {
+	w := &slowWriter{delay: time.Millisecond}
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+
+	for i := 0; i < 10; i++ {
+		lg.Infof("queued %d", i)
+	}
+	lg.SetSync(true)
+	lg.Info("direct")
+
+	var want strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&want, "[TEST] [I]   queued %d\n", i)
+	}
+	want.WriteString("[TEST] [I]   direct\n")
+	if w.String() != want.String() {
+		t.Fatalf("output:\n%s\nwant:\n%s", w, want.String())
+	}
+}
+
+func TestTimestampAtEnqueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		log    func(lg *Logger)
+		want   string // the queued line
+	}{
+		{"text", FormatText, func(lg *Logger) { lg.Info("queued") },
+			"[TEST] 2026/10/14 09:05:08 [I]   queued\n"},
+		{"json", FormatJSON, func(lg *Logger) { lg.Info("queued") },
+			`{"time":"2026-10-14T09:05:08Z","level":"info","module":"TEST","msg":"queued"}` + "\n"},
+		{"logfmt", FormatLogfmt, func(lg *Logger) { lg.Info("queued") },
+			"time=2026-10-14T09:05:08Z level=info module=TEST msg=queued\n"},
+		{"lazy", FormatText, func(lg *Logger) { lg.Info(func() string { return "queued" }) },
+			"[TEST] 2026/10/14 09:05:08 [I]   queued\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newBlockedWriter()
+			lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithFormat(tt.format))
+			defer lg.Close()
+			clock := &testClock{now: time.Date(2026, 10, 14, 9, 5, 7, 0, time.UTC)}
+			lg.SetClock(clock.Now)
+
+			lg.Info("first")
+			<-w.entered // the consumer is stuck writing
+			clock.Add(time.Second)
+			tt.log(lg)
+			clock.Add(time.Hour) // by the time the line is written
+			close(w.release)
+			lg.Flush()
+
+			lines := strings.SplitAfter(w.String(), "\n")
+			if len(lines) != 3 || lines[1] != tt.want {
+				t.Fatalf("got %q, want the second line %q", w, tt.want)
+			}
+		})
+	}
+}
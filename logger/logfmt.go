@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// writeLogfmt writes the message as a logfmt line: time, level, module and
+// msg first, then the fields sorted by key
+func (lg *core) writeLogfmt(b *bytes.Buffer, m logMessage, now time.Time) {
+	writeLogfmtPair(b, "time", lg.opts.Load().jsonTime(now))
+	writeLogfmtPair(b, "level", levelNames[m.level])
+	writeLogfmtPair(b, "module", m.module)
+	writeLogfmtPair(b, "msg", unlink(stripANSI(m.msg)))
+	if m.caller != "" {
+		writeLogfmtPair(b, "caller", m.caller)
+	}
+	if m.err != nil {
+		writeLogfmtPair(b, "error", m.err.Error())
+	}
+
+	fields := append([]Field(nil), m.fields...)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	for _, f := range fields {
+		writeLogfmtPair(b, logfmtKey(f.Key), fmt.Sprint(machineValue(f.Value)))
+	}
+	b.WriteByte('\n')
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(logfmtValue(value))
+}
+
+// logfmtValue quotes values that would otherwise be split or ambiguous,
+// invalid UTF-8 is escaped by the quoting
+func logfmtValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// logfmtKey replaces the characters a key can't contain
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, k)
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseLogfmt splits a logfmt line into its key value pairs
+func parseLogfmt(line string) ([][2]string, bool) {
+	var pairs [][2]string
+	for line != "" {
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \"") {
+			return nil, false
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			// find the closing quote, skipping escaped characters
+			end := 1
+			for end < len(rest) && rest[end] != '"' {
+				if rest[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(rest) {
+				return nil, false
+			}
+			v, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			value, line = v, rest[end+1:]
+		} else {
+			value, line, _ = strings.Cut(rest, " ")
+			line = " " + line
+		}
+		pairs = append(pairs, [2]string{key, value})
+		if line == "" || line == " " {
+			break
+		}
+		if !strings.HasPrefix(line, " ") {
+			return nil, false
+		}
+		line = line[1:]
+	}
+	return pairs, true
+}
+
+func TestLogfmtValue(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", `""`},
+		{"plain", "plain"},
+		{"with space", `"with space"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"a=b", `"a=b"`},
+		{"line\nbreak", `"line\nbreak"`},
+		{"tab\there", `"tab\there"`},
+		{`back\slash`, `"back\\slash"`},
+		{"\x1b[31m", `"\x1b[31m"`},
+		{"ünïcödé", "ünïcödé"},
+		{"日本語", "日本語"},
+		{"zero\u200bwidth", `"zero\u200bwidth"`},
+		{"-1.5e3", "-1.5e3"},
+		{"bad\xffutf8", `"bad\xffutf8"`},
+	}
+	for _, tt := range tests {
+		if got := logfmtValue(tt.in); got != tt.want {
+			t.Errorf("logfmtValue(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtKey(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "_"},
+		{"user_id", "user_id"},
+		{"a b", "a_b"},
+		{"k=v", "k_v"},
+		{`q"`, "q_"},
+		{"new\nline", "new_line"},
+		{"ключ", "ключ"},
+	}
+	for _, tt := range tests {
+		if got := logfmtKey(tt.in); got != tt.want {
+			t.Errorf("logfmtKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtOutput(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 5, 7, 0, time.UTC)
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{"message", func(lg *Logger) { lg.Info("hello") },
+			`time=2026-10-14T09:05:07Z level=info module=HTTP msg=hello`},
+		{"quoted message", func(lg *Logger) { lg.Warn(`disk "sda" is 90% full`) },
+			`time=2026-10-14T09:05:07Z level=warn module=HTTP msg="disk \"sda\" is 90% full"`},
+		{"sorted fields", func(lg *Logger) { lg.WithFields(map[string]any{"z": 1, "a": "x y", "m": nil}).Error("failed") },
+			`time=2026-10-14T09:05:07Z level=error module=HTTP msg=failed a="x y" m=<nil> z=1`},
+		{"colors stripped", func(lg *Logger) { lg.Print(ColorString(Red, "red")) },
+			`time=2026-10-14T09:05:07Z level=print module=HTTP msg=red`},
+		{"empty message", func(lg *Logger) { lg.Info("") },
+			`time=2026-10-14T09:05:07Z level=info module=HTTP msg=""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger("HTTP", WithWriters(buf), WithSync(true), WithFormat(FormatLogfmt))
+			defer lg.Close()
+			lg.SetClock(func() time.Time { return now })
+
+			tt.log(lg)
+			if got := buf.String(); got != tt.want+"\n" {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzLogfmtRoundTrip(f *testing.F) {
+	f.Add("hello world", "key", "value")
+	f.Add("", "", "")
+	f.Add("line\nbreak", "a=b", `"quoted"`)
+	f.Add("ünïcödé \\ tab\t", "k y", "\x00\x7f")
+	f.Add("\xff\xfe", "​", "=")
+
+	buf := &syncBuffer{}
+	lg := NewLogger("FUZZ", WithWriters(buf), WithSync(true), WithFormat(FormatLogfmt))
+	defer lg.Close()
+	f.Fuzz(func(t *testing.T, msg, key, value string) {
+		buf.mu.Lock()
+		buf.buf.Reset()
+		buf.mu.Unlock()
+
+		lg.WithField(key, value).Info(msg)
+		line := strings.TrimSuffix(buf.String(), "\n")
+		if strings.Contains(line, "\n") {
+			t.Fatalf("line break in %q", line)
+		}
+		pairs, ok := parseLogfmt(line)
+		if !ok || len(pairs) != 5 {
+			t.Fatalf("can't parse %q: %q", line, pairs)
+		}
+		if pairs[3][0] != "msg" || pairs[3][1] != unlink(stripANSI(msg)) {
+			t.Fatalf("msg = %q, want %q from %q", pairs[3][1], unlink(stripANSI(msg)), line)
+		}
+		if pairs[4][0] != logfmtKey(key) || pairs[4][1] != value {
+			t.Fatalf("field = %q, want %q=%q from %q", pairs[4], logfmtKey(key), value, line)
+		}
+	})
+}
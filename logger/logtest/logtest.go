@@ -0,0 +1,105 @@
+// Package logtest records the messages of a logger.Logger for assertions
+package logtest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vizn3r/go-lib/logger"
+)
+
+// Entry is a recorded message
+type Entry struct {
+	Level   logger.LogLevel
+	Module  string
+	Message string
+	Fields  map[string]any
+	Time    time.Time
+}
+
+// Recorder stores the entries of a logger, safe for concurrent use
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New returns a synchronous, colorless logger logging every level and the
+// recorder capturing its messages. Fatal is recorded without exiting. The
+// logger is closed on tb.Cleanup.
+func New(tb testing.TB) (*logger.Logger, *Recorder) {
+	tb.Helper()
+
+	lg := logger.NewLogger("TEST",
+		logger.WithColor(logger.Grey),
+		logger.WithWriters(io.Discard),
+		logger.WithSync(true),
+		logger.WithNoColor(),
+		logger.WithLevel(logger.LevelTrace),
+	)
+	lg.SetExitFunc(func(int) {})
+
+	rec := &Recorder{}
+	lg.AddHook(rec.record)
+	tb.Cleanup(lg.Close)
+
+	return lg, rec
+}
+
+func (r *Recorder) record(level logger.LogLevel, module string, msg string, fields map[string]any) {
+	r.mu.Lock()
+	r.entries = append(r.entries, Entry{
+		Level:   level,
+		Module:  module,
+		Message: msg,
+		Fields:  fields,
+		Time:    time.Now(),
+	})
+	r.mu.Unlock()
+}
+
+// Entries returns a copy of the recorded entries, oldest first
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// LastEntry returns the newest entry, false if nothing was recorded
+func (r *Recorder) LastEntry() (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) == 0 {
+		return Entry{}, false
+	}
+	return r.entries[len(r.entries)-1], true
+}
+
+// Count returns how many entries at level contain substr
+func (r *Recorder) Count(level logger.LogLevel, substr string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, e := range r.entries {
+		if e.Level == level && strings.Contains(e.Message, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+// Contains reports whether an entry at level contains substr
+func (r *Recorder) Contains(level logger.LogLevel, substr string) bool {
+	return r.Count(level, substr) > 0
+}
+
+// Reset drops the recorded entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
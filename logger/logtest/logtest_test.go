@@ -0,0 +1,73 @@
+package logtest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/vizn3r/go-lib/logger"
+)
+
+func TestRecorder(t *testing.T) {
+	lg, rec := New(t)
+	if _, ok := rec.LastEntry(); ok {
+		t.Fatal("LastEntry of an empty recorder")
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				lg.WithField("user_id", 42).Errorf("user %d failed", 42)
+				lg.Trace("trace")
+				_ = rec.Entries()
+				rec.Contains(logger.LevelError, "42")
+			}
+		}()
+	}
+	wg.Wait()
+
+	tests := []struct {
+		name  string
+		level logger.LogLevel
+		sub   string
+		want  int
+	}{
+		{"errors", logger.LevelError, "user 42", 200},
+		{"trace", logger.LevelTrace, "trace", 200},
+		{"other level", logger.LevelWarn, "user 42", 0},
+		{"missing", logger.LevelError, "nobody", 0},
+	}
+	for _, tt := range tests {
+		if got := rec.Count(tt.level, tt.sub); got != tt.want {
+			t.Errorf("%s: Count = %d, want %d", tt.name, got, tt.want)
+		}
+		if got := rec.Contains(tt.level, tt.sub); got != (tt.want > 0) {
+			t.Errorf("%s: Contains = %v", tt.name, got)
+		}
+	}
+	if n := len(rec.Entries()); n != 400 {
+		t.Fatalf("%d entries, want 400", n)
+	}
+
+	lg.Sub("DB", logger.Blue).Warnw("slow", "ms", 12)
+	last, ok := rec.LastEntry()
+	if !ok || last.Level != logger.LevelWarn || last.Module != "TEST/DB" || last.Message != "slow" ||
+		last.Fields["ms"] != 12 || last.Time.IsZero() {
+		t.Fatalf("LastEntry = %+v", last)
+	}
+
+	rec.Reset()
+	if len(rec.Entries()) != 0 {
+		t.Fatal("entries left after Reset")
+	}
+}
+
+func TestFatalDoesntExit(t *testing.T) {
+	lg, rec := New(t)
+	lg.Fatal("boom")
+	if !rec.Contains(logger.LevelFatal, "boom") {
+		t.Fatal("Fatal wasn't recorded")
+	}
+}
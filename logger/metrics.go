@@ -0,0 +1,22 @@
+package logger
+
+// Metrics receives log volume, implement it with Prometheus counters and
+// gauges or anything else without this package depending on them
+type Metrics interface {
+	// IncMessages counts a written message
+	IncMessages(module string, level LogLevel)
+	// SetQueueLen reports the messages waiting for the consumer
+	SetQueueLen(n int)
+	// SetDropped reports the total of messages dropped by backpressure
+	SetDropped(n uint64)
+}
+
+// NewMetricsHook returns a hook updating m for every message written by
+// lg, register it with lg.AddHook
+func NewMetricsHook(lg *Logger, m Metrics) Hook {
+	return func(level LogLevel, module string, msg string, fields map[string]any) {
+		m.IncMessages(module, level)
+		m.SetQueueLen(lg.QueueLen())
+		m.SetDropped(lg.Dropped())
+	}
+}
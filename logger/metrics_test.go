@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeMetrics keeps what a metrics hook reported
+type fakeMetrics struct {
+	mu       sync.Mutex
+	messages map[string]int // "module level"
+	queue    []int
+	dropped  uint64
+}
+
+func (m *fakeMetrics) IncMessages(module string, level LogLevel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.messages == nil {
+		m.messages = map[string]int{}
+	}
+	m.messages[module+" "+level.String()]++
+}
+
+func (m *fakeMetrics) SetQueueLen(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, n)
+}
+
+func (m *fakeMetrics) SetDropped(n uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped = n
+}
+
+func TestMetricsHook(t *testing.T) {
+	lg, _ := newTestLogger(t, WithLevel(LevelInfo))
+	m := &fakeMetrics{}
+	lg.AddHook(NewMetricsHook(lg, m))
+
+	db := lg.Sub("DB", "")
+	lg.Info("a")
+	lg.Info("b")
+	lg.Warn("c")
+	lg.Debug("filtered")
+	db.Error("d")
+	db.Info("e")
+	lg.Error("f")
+
+	want := map[string]int{
+		"TEST info":     2,
+		"TEST warn":     1,
+		"TEST error":    1,
+		"TEST/DB error": 1,
+		"TEST/DB info":  1,
+	}
+	if fmt.Sprint(m.messages) != fmt.Sprint(want) {
+		t.Fatalf("messages = %v, want %v", m.messages, want)
+	}
+	if len(m.queue) != 6 || m.dropped != 0 {
+		t.Fatalf("queue = %v, dropped = %d", m.queue, m.dropped)
+	}
+}
+
+func TestMetricsHookBacklog(t *testing.T) {
+	w := newBlockedWriter()
+	lg := NewLogger("TEST", WithWriters(w), WithBufferSize(4), WithNoColor(), WithPrintTime(false))
+	lg.SetBackpressure(DropNewest)
+	m := &fakeMetrics{}
+	lg.AddHook(NewMetricsHook(lg, m))
+
+	lg.Info("m0")
+	<-w.entered
+	for i := 1; i <= 10; i++ {
+		lg.Infof("m%d", i)
+	}
+	if got := lg.QueueLen(); got != 4 {
+		t.Fatalf("QueueLen = %d, want 4", got)
+	}
+	close(w.release)
+	lg.Close()
+
+	if lg.QueueLen() != 0 {
+		t.Fatalf("QueueLen = %d after Close", lg.QueueLen())
+	}
+	if m.dropped != 6 || m.messages["TEST info"] != 5 {
+		t.Fatalf("dropped = %d, messages = %v", m.dropped, m.messages)
+	}
+	// m0's hook ran before its write blocked, the others saw the backlog
+	if fmt.Sprint(m.queue) != "[0 3 2 1 0]" {
+		t.Fatalf("queue lengths = %v", m.queue)
+	}
+}
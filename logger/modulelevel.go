@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Levels by upper-cased module name, "*" applies to modules without entry
+var (
+	moduleLevels   = map[string]LogLevel{}
+	moduleLevelsMu sync.RWMutex
+)
+
+func init() {
+	if env := os.Getenv("LOG_LEVELS"); env != "" {
+		parseModuleLevels(env)
+	}
+}
+
+// parseModuleLevels reads "HTTP=debug,DB=warn,*=info", invalid entries are
+// skipped and reported in a single warning on stderr
+func parseModuleLevels(spec string) {
+	var invalid []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		module, name, ok := strings.Cut(entry, "=")
+		level, err := ParseLevel(strings.TrimSpace(name))
+		if !ok || err != nil || strings.TrimSpace(module) == "" {
+			invalid = append(invalid, entry)
+			continue
+		}
+		moduleLevels[strings.ToUpper(strings.TrimSpace(module))] = level
+	}
+	if len(invalid) > 0 {
+		fmt.Fprintf(os.Stderr, "logger: ignoring invalid LOG_LEVELS entries: %s\n", strings.Join(invalid, ", "))
+	}
+}
+
+// SetModuleLevel sets the level of loggers for module, matched case
+// insensitively, or of every module without own entry for "*". It applies
+// to loggers created afterwards and to the registered ones.
+func SetModuleLevel(module string, level LogLevel) {
+	key := strings.ToUpper(module)
+	moduleLevelsMu.Lock()
+	moduleLevels[key] = level
+	moduleLevelsMu.Unlock()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for name, lg := range registry {
+		if name := strings.ToUpper(name); name == key || key == "*" && !hasModuleLevel(name) {
+			lg.SetLevel(level)
+		}
+	}
+}
+
+func hasModuleLevel(key string) bool {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+	_, ok := moduleLevels[key]
+	return ok
+}
+
+// moduleLevel returns the level configured for module, if any
+func moduleLevel(module string) (LogLevel, bool) {
+	moduleLevelsMu.RLock()
+	defer moduleLevelsMu.RUnlock()
+
+	if l, ok := moduleLevels[strings.ToUpper(module)]; ok {
+		return l, true
+	}
+	l, ok := moduleLevels["*"]
+	return l, ok
+}
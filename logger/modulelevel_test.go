@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+)
+
+// keepModuleLevels restores the module levels after the test
+func keepModuleLevels(t *testing.T) {
+	moduleLevelsMu.Lock()
+	saved := maps.Clone(moduleLevels)
+	clear(moduleLevels)
+	moduleLevelsMu.Unlock()
+	t.Cleanup(func() {
+		moduleLevelsMu.Lock()
+		defer moduleLevelsMu.Unlock()
+		moduleLevels = saved
+	})
+}
+
+// captureStderr returns what f wrote to os.Stderr
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	f()
+	os.Stderr = stderr
+	w.Close()
+	out, _ := io.ReadAll(r)
+	r.Close()
+	return string(out)
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		warning string
+	}{
+		{"HTTP=debug,DB=warn", "map[DB:warn HTTP:debug]", ""},
+		{" http = trace , *=error,", "map[*:error HTTP:trace]", ""},
+		{"Db=info,DB=error", "map[DB:error]", ""},
+		{"HTTP=loud,=info,DB,CACHE=debug", "map[CACHE:debug]",
+			"logger: ignoring invalid LOG_LEVELS entries: HTTP=loud, =info, DB\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			keepModuleLevels(t)
+			warning := captureStderr(t, func() { parseModuleLevels(tt.spec) })
+			if got := fmt.Sprint(moduleLevels); got != tt.want {
+				t.Errorf("levels = %s, want %s", got, tt.want)
+			}
+			if warning != tt.warning {
+				t.Errorf("warning = %q, want %q", warning, tt.warning)
+			}
+		})
+	}
+}
+
+func TestModuleLevelsFilter(t *testing.T) {
+	keepModuleLevels(t)
+	t.Setenv("LOG_LEVELS", "HTTP=debug,db=warn,*=error")
+	parseModuleLevels(os.Getenv("LOG_LEVELS"))
+
+	tests := []struct {
+		module string
+		opts   []Option
+		want   string // levels written out of debug, info, warn and error
+	}{
+		{"HTTP", nil, "DIWE"},
+		{"http", nil, "DIWE"},
+		{"DB", nil, "WE"},
+		{"CACHE", nil, "E"},
+		{"CACHE", []Option{WithLevel(LevelDebug)}, "E"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger(tt.module, append([]Option{WithWriters(buf), WithSync(true), WithNoColor(), WithPrintTime(false)}, tt.opts...)...)
+			defer lg.Close()
+			lg.Debug("D")
+			lg.Info("I")
+			lg.Warn("W")
+			lg.Error("E")
+
+			var got strings.Builder
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				got.WriteString(line[len(line)-1:])
+			}
+			if got.String() != tt.want {
+				t.Fatalf("wrote %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSetModuleLevel(t *testing.T) {
+	keepModuleLevels(t)
+	unregister(t, "MODA", "modb")
+	a := GetOrCreate("MODA", "", io.Discard)
+	b := GetOrCreate("modb", "", io.Discard)
+	t.Cleanup(func() { a.Close(); b.Close() })
+
+	SetModuleLevel("moda", LevelError)
+	if a.GetLevel() != LevelError || b.GetLevel() != LevelPrint {
+		t.Fatalf("levels = %v, %v after setting MODA", a.GetLevel(), b.GetLevel())
+	}
+
+	// the default leaves modules with their own entry alone
+	SetModuleLevel("*", LevelWarn)
+	if a.GetLevel() != LevelError || b.GetLevel() != LevelWarn {
+		t.Fatalf("levels = %v, %v after setting *", a.GetLevel(), b.GetLevel())
+	}
+
+	sub := a.WithModule("MODB", "")
+	if sub.GetLevel() != LevelWarn {
+		t.Fatalf("WithModule level = %v", sub.GetLevel())
+	}
+	c := NewLogger("MODC", WithWriters(io.Discard))
+	defer c.Close()
+	if c.GetLevel() != LevelWarn {
+		t.Fatalf("new logger level = %v", c.GetLevel())
+	}
+}
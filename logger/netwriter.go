@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NetOption configures NewNetWriter
+type NetOption func(*NetWriter)
+
+// NetBuffer limits what is kept while disconnected to maxBytes and
+// maxMessages, the oldest lines are dropped beyond that. 0 keeps the
+// default of 1MB and 10000 lines.
+func NetBuffer(maxBytes, maxMessages int) NetOption {
+	return func(w *NetWriter) {
+		if maxBytes > 0 {
+			w.maxBytes = maxBytes
+		}
+		if maxMessages > 0 {
+			w.maxMessages = maxMessages
+		}
+	}
+}
+
+// NetTimeout bounds dialing and each write, 5s by default
+func NetTimeout(d time.Duration) NetOption {
+	return func(w *NetWriter) { w.timeout = d }
+}
+
+// NetBackoff sets the delay between reconnects, doubling from min up to
+// max. The defaults are 100ms and 30s.
+func NetBackoff(min, max time.Duration) NetOption {
+	return func(w *NetWriter) { w.minBackoff, w.maxBackoff = min, max }
+}
+
+// NetWriter ships newline delimited lines to a TCP or UDP collector. Write
+// only queues the line, a background goroutine sends it and reconnects
+// with exponential backoff, so the logger never waits for the network.
+type NetWriter struct {
+	network, addr          string
+	timeout                time.Duration
+	minBackoff, maxBackoff time.Duration
+	maxBytes, maxMessages  int
+
+	mu     sync.Mutex
+	queue  [][]byte
+	size   int
+	closed bool
+
+	wake    chan struct{} // lines were queued
+	quit    chan struct{} // closed by Close
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+// NewNetWriter returns a writer sending to addr, the first connection is
+// made in the background
+func NewNetWriter(network, addr string, opts ...NetOption) *NetWriter {
+	w := &NetWriter{
+		network:     network,
+		addr:        addr,
+		timeout:     5 * time.Second,
+		minBackoff:  100 * time.Millisecond,
+		maxBackoff:  30 * time.Second,
+		maxBytes:    1 << 20,
+		maxMessages: 10000,
+		wake:        make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(w)
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, missing newlines are added
+func (w *NetWriter) Write(p []byte) (int, error) {
+	line := make([]byte, len(p), len(p)+1)
+	copy(line, p)
+	if !bytes.HasSuffix(line, []byte{'\n'}) {
+		line = append(line, '\n')
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, net.ErrClosed
+	}
+	w.queue = append(w.queue, line)
+	w.size += len(line)
+	w.trim()
+	w.mu.Unlock()
+
+	w.signal()
+	return len(p), nil
+}
+
+// Dropped returns the number of lines dropped because the buffer was full
+func (w *NetWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close sends what is still buffered if the collector is reachable within
+// the timeout and stops the background goroutine
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.quit)
+	<-w.done
+	return nil
+}
+
+func (w *NetWriter) managed() {}
+
+func (w *NetWriter) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// trim drops the oldest lines beyond the limits, must be called with mu
+// held
+func (w *NetWriter) trim() {
+	for len(w.queue) > 1 && (w.size > w.maxBytes || len(w.queue) > w.maxMessages) {
+		w.size -= len(w.queue[0])
+		w.queue[0] = nil
+		w.queue = w.queue[1:]
+		w.dropped.Add(1)
+	}
+}
+
+// take removes and returns every queued line
+func (w *NetWriter) take() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lines := w.queue
+	w.queue, w.size = nil, 0
+	return lines
+}
+
+// requeue puts unsent lines back in front of the ones queued meanwhile
+func (w *NetWriter) requeue(lines [][]byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.queue = append(lines, w.queue...)
+	w.size = 0
+	for _, l := range w.queue {
+		w.size += len(l)
+	}
+	w.trim()
+}
+
+func (w *NetWriter) run() {
+	defer close(w.done)
+
+	var conn net.Conn
+	backoff := w.minBackoff
+	closing := false
+	for {
+		select {
+		case <-w.wake:
+		case <-w.quit:
+			closing = true
+		}
+
+		lines := w.take()
+		sent := 0
+		var err error
+		if len(lines) > 0 && conn == nil {
+			conn, err = net.DialTimeout(w.network, w.addr, w.timeout)
+		}
+		for err == nil && sent < len(lines) {
+			conn.SetWriteDeadline(time.Now().Add(w.timeout))
+			if _, err = conn.Write(lines[sent]); err == nil {
+				sent++
+			}
+		}
+
+		if err == nil {
+			backoff = w.minBackoff
+			if closing {
+				if conn != nil {
+					conn.Close()
+				}
+				return
+			}
+			continue
+		}
+
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+		w.requeue(lines[sent:])
+		if closing {
+			return // the final flush failed
+		}
+
+		// Wait before reconnecting, lines logged meanwhile are buffered
+		select {
+		case <-time.After(backoff):
+		case <-w.quit:
+			closing = true // one last attempt without waiting
+		}
+		backoff = min(backoff*2, w.maxBackoff)
+		w.signal()
+	}
+}
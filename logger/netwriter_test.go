@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// acceptLines accepts one connection on l and sends every line read from
+// it, the channel is closed when the connection ends
+func acceptLines(t *testing.T, l net.Listener) <-chan string {
+	t.Helper()
+
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	return lines
+}
+
+func nextLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("no line received")
+		return ""
+	}
+}
+
+func TestNetWriterTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	lines := acceptLines(t, l)
+
+	w := NewNetWriter("tcp", l.Addr().String())
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+	lg.Info("first")
+	lg.Warn("second")
+	lg.Close()
+
+	for _, want := range []string{"[TEST] [I]   first", "[TEST] [W] ? second"} {
+		if got := nextLine(t, lines); got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+	if _, err := w.Write([]byte("late")); err == nil {
+		t.Fatal("write after Close succeeded")
+	}
+}
+
+func TestNetWriterUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	w := NewNetWriter("udp", pc.LocalAddr().String())
+	defer w.Close()
+	w.Write([]byte("no newline"))
+	w.Write([]byte("with newline\n"))
+
+	buf := make([]byte, 1024)
+	for _, want := range []string{"no newline\n", "with newline\n"} {
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Fatalf("datagram %q, want %q", got, want)
+		}
+	}
+}
+
+func TestNetWriterReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// the collector goes away after the first line
+	first := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		first <- line
+		conn.Close()
+	}()
+
+	w := NewNetWriter("tcp", l.Addr().String(), NetBackoff(time.Millisecond, 10*time.Millisecond))
+	defer w.Close()
+	w.Write([]byte("before\n"))
+	if got := <-first; got != "before\n" {
+		t.Fatalf("got %q", got)
+	}
+
+	// lines written to the dead connection may be lost, the writer must
+	// notice and send the later ones on a new connection
+	lines := acceptLines(t, l)
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; ; i++ {
+		w.Write([]byte(fmt.Sprintf("after %d\n", i)))
+		select {
+		case line := <-lines:
+			if !strings.HasPrefix(line, "after ") {
+				t.Fatalf("got %q after reconnecting", line)
+			}
+			return
+		case <-time.After(5 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("writer didn't reconnect")
+		}
+	}
+}
+
+func TestNetWriterBufferWhileDown(t *testing.T) {
+	// reserve a port with nothing listening on it yet
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	w := NewNetWriter("tcp", addr, NetBuffer(0, 3), NetBackoff(time.Millisecond, 5*time.Millisecond))
+	defer w.Close()
+	for i := 0; i < 5; i++ {
+		w.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+
+	l, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("port was taken meanwhile: %v", err)
+	}
+	defer l.Close()
+	lines := acceptLines(t, l)
+
+	for _, want := range []string{"line 2", "line 3", "line 4"} {
+		if got := nextLine(t, lines); got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+	if got := w.Dropped(); got != 2 {
+		t.Fatalf("Dropped = %d, want 2", got)
+	}
+}
+
+func TestNetWriterCloseUnreachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	w := NewNetWriter("tcp", addr, NetTimeout(50*time.Millisecond), NetBackoff(time.Hour, time.Hour))
+	w.Write([]byte("lost\n"))
+
+	done := make(chan struct{})
+	go func() {
+		w.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close hangs while the collector is unreachable")
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import "os"
+
+// Interface is the logging surface of *Logger, accept it to let callers
+// pass Nop() for silence
+type Interface interface {
+	Log(level LogLevel, v ...any)
+	Print(v ...any)
+	Debug(v ...any)
+	Info(v ...any)
+	Warn(v ...any)
+	Error(v ...any)
+	Fatal(v ...any)
+
+	Logf(level LogLevel, format string, v ...any)
+	Printf(format string, v ...any)
+	Debugf(format string, v ...any)
+	Infof(format string, v ...any)
+	Warnf(format string, v ...any)
+	Errorf(format string, v ...any)
+	Fatalf(format string, v ...any)
+
+	Flush()
+	Close()
+}
+
+var _ Interface = (*Logger)(nil)
+
+type nop struct{}
+
+// nopExit is called by Fatal of Nop, replaceable in tests
+var nopExit = os.Exit
+
+// Nop returns a logger discarding everything, without a goroutine. Fatal
+// still exits the process with code 1, like FromContext without a logger.
+func Nop() Interface {
+	return nop{}
+}
+
+func (nop) Log(LogLevel, ...any) {}
+func (nop) Print(...any)         {}
+func (nop) Debug(...any)         {}
+func (nop) Info(...any)          {}
+func (nop) Warn(...any)          {}
+func (nop) Error(...any)         {}
+func (nop) Fatal(...any)         { nopExit(1) }
+
+func (nop) Logf(LogLevel, string, ...any) {}
+func (nop) Printf(string, ...any)         {}
+func (nop) Debugf(string, ...any)         {}
+func (nop) Infof(string, ...any)          {}
+func (nop) Warnf(string, ...any)          {}
+func (nop) Errorf(string, ...any)         {}
+func (nop) Fatalf(string, ...any)         { nopExit(1) }
+
+func (nop) Flush() {}
+func (nop) Close() {}
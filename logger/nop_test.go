@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestNop(t *testing.T) {
+	before := runtime.NumGoroutine()
+	l := Nop()
+	l.Print("x")
+	l.Debug("x")
+	l.Info("x")
+	l.Warn("x")
+	l.Error("x")
+	l.Log(LevelError, "x")
+	l.Printf("%d", 1)
+	l.Debugf("%d", 1)
+	l.Infof("%d", 1)
+	l.Warnf("%d", 1)
+	l.Errorf("%d", 1)
+	l.Logf(LevelError, "%d", 1)
+	l.Flush()
+	l.Close()
+	l.Close()
+	l.Flush()
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("Nop started %d goroutines", after-before)
+	}
+}
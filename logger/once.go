@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Once logs the message only the first time key is seen by lg or any
+// logger sharing its writers
+func (lg *Logger) Once(level LogLevel, key string, v ...any) {
+	lg.once(1, level, key, v...)
+}
+
+// WarnOnce logs a warning only the first time key is seen, handy for
+// deprecation notices
+func (lg *Logger) WarnOnce(key string, v ...any) {
+	lg.once(1, LevelWarn, key, v...)
+}
+
+func (lg *Logger) once(depth int, level LogLevel, key string, v ...any) {
+	if !lg.enabled(level) {
+		return
+	}
+
+	lg.onceMu.Lock()
+	_, seen := lg.onceKeys[key]
+	if !seen {
+		if lg.onceKeys == nil {
+			lg.onceKeys = map[string]struct{}{}
+		}
+		lg.onceKeys[key] = struct{}{}
+	}
+	lg.onceMu.Unlock()
+
+	if !seen {
+		lg.logDepth(depth+1, level, v...)
+	}
+}
+
+// ResetOnce forgets every key seen by Once and WarnOnce
+func (lg *Logger) ResetOnce() {
+	lg.onceMu.Lock()
+	lg.onceKeys = nil
+	lg.onceMu.Unlock()
+}
+
+// limiter lets one message through per interval
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (l *limiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.Sub(l.last) < l.interval {
+		return false
+	}
+	l.last = now
+	return true
+}
+
+// Every returns a derived logger that drops messages until d has elapsed
+// since the last one it wrote, for progress lines in tight loops. Keep the
+// returned logger around, every call creates a new interval.
+func (lg *Logger) Every(d time.Duration) *Logger {
+	child := lg.derive()
+	child.every = &limiter{interval: d}
+	return child
+}
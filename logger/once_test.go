@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOnce(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetPrintModule(false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.WarnOnce("old-api", "old API is deprecated")
+			lg.Once(LevelInfo, "ready", "ready")
+			lg.Sub("C", Blue).WarnOnce("old-api", "from a sub logger")
+		}()
+	}
+	wg.Wait()
+
+	if got := buf.String(); strings.Count(got, "\n") != 2 ||
+		strings.Count(got, "[W] ? old API is deprecated\n") != 1 ||
+		strings.Count(got, "[I]   ready\n") != 1 {
+		t.Fatalf("output:\n%s", got)
+	}
+
+	lg.ResetOnce()
+	lg.WarnOnce("old-api", "again")
+	if !strings.HasSuffix(buf.String(), "again\n") {
+		t.Fatal("key still known after ResetOnce")
+	}
+}
+
+func TestOnceFiltered(t *testing.T) {
+	lg, buf := newTestLogger(t, WithLevel(LevelError))
+	lg.WarnOnce("k", "hidden")
+	lg.SetLevel(LevelWarn)
+	lg.WarnOnce("k", "shown")
+	if !strings.Contains(buf.String(), "shown") {
+		t.Fatal("a filtered message used up its key")
+	}
+}
+
+func TestEvery(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	steps := []struct {
+		advance time.Duration
+		written bool
+	}{
+		{0, true},
+		{time.Second, false},
+		{3 * time.Second, false},
+		{time.Second, true},
+		{4 * time.Second, false},
+		{6 * time.Second, true},
+	}
+
+	lg, buf := newTestLogger(t)
+	lg.SetClock(clock.Now)
+	every := lg.Every(5 * time.Second)
+	for i, s := range steps {
+		clock.Add(s.advance)
+		before := buf.String()
+		every.Info("progress")
+		if got := buf.String() != before; got != s.written {
+			t.Fatalf("step %d: written = %v, want %v", i, got, s.written)
+		}
+	}
+
+	lg.Info("unlimited")
+	lg.Info("unlimited")
+	if strings.Count(buf.String(), "unlimited") != 2 {
+		t.Fatal("Every limited its parent")
+	}
+}
+
+func TestEveryConcurrent(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	every := lg.Every(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			every.Info("progress")
+		}()
+	}
+	wg.Wait()
+	if n := strings.Count(buf.String(), "progress"); n != 1 {
+		t.Fatalf("%d messages within one interval", n)
+	}
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"time"
+)
+
+// Layout of the timestamp in text output, matches log.LstdFlags
+const defaultTimeFormat = "2006/01/02 15:04:05"
+
+// renderOptions are the settings used when rendering a message. They are
+// swapped as a whole so setters are safe while messages are in flight.
+type renderOptions struct {
+	format      Format
+	printTime   bool
+	printModule bool
+	timeFormat  string
+	utc         bool
+	highlight   bool
+	sanitize    bool
+	hyperlinks  ColorMode
+	theme       *Theme
+	prefix      string
+	msgPrefix   bool
+	wholeLine   bool
+	layout      []layoutPart // see SetPrefixFormat, nil for the default
+}
+
+// updateOpts applies fn to a copy of the options and publishes it
+func (lg *core) updateOpts(fn func(o *renderOptions)) {
+	lg.optsMu.Lock()
+	defer lg.optsMu.Unlock()
+
+	o := *lg.opts.Load()
+	fn(&o)
+	lg.opts.Store(&o)
+}
+
+func (o *renderOptions) timestamp(t time.Time) string {
+	if o.utc {
+		t = t.UTC()
+	}
+	return t.Format(o.timeFormat)
+}
+
+// appendTimestamp is timestamp without the allocation of the string
+func (o *renderOptions) appendTimestamp(b *bytes.Buffer, t time.Time) {
+	if o.utc {
+		t = t.UTC()
+	}
+	b.Write(t.AppendFormat(b.AvailableBuffer(), o.timeFormat))
+}
+
+func (o *renderOptions) jsonTime(t time.Time) string {
+	if o.utc {
+		t = t.UTC()
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// SetFormat switches between the colored text output, JSON lines and
+// logfmt. Writers wrapped with TextTo, JSONTo or LogfmtTo keep their own.
+func (lg *Logger) SetFormat(format Format) {
+	lg.updateOpts(func(o *renderOptions) { o.format = format })
+}
+
+// SetPrintTime toggles the timestamp in text output
+func (lg *Logger) SetPrintTime(print bool) {
+	lg.updateOpts(func(o *renderOptions) { o.printTime = print })
+}
+
+// SetPrintModule toggles the "[MODULE]" prefix in text output, independent
+// of the timestamp
+func (lg *Logger) SetPrintModule(print bool) {
+	lg.updateOpts(func(o *renderOptions) { o.printModule = print })
+}
+
+// SetHighlighting toggles keyword and number highlighting of message
+// bodies in colored text output, on by default for every level
+func (lg *Logger) SetHighlighting(highlight bool) {
+	lg.updateOpts(func(o *renderOptions) { o.highlight = highlight })
+}
+
+// SetColorWholeLine tints the whole message of Warn and above in the
+// color of the level tag instead of only the tag
+func (lg *Logger) SetColorWholeLine(whole bool) {
+	lg.updateOpts(func(o *renderOptions) { o.wholeLine = whole })
+}
+
+// SetSanitize toggles escaping of control characters and newlines in
+// messages of every level but Print, on by default so logged input can't
+// forge lines or send escapes to the terminal
+func (lg *Logger) SetSanitize(sanitize bool) {
+	lg.updateOpts(func(o *renderOptions) { o.sanitize = sanitize })
+}
+
+// SetTimeFormat sets the timestamp layout of text output using Go's
+// reference time, e.g. time.RFC3339 or "15:04:05.000"
+func (lg *Logger) SetTimeFormat(layout string) {
+	if layout == "" {
+		layout = defaultTimeFormat
+	}
+	lg.updateOpts(func(o *renderOptions) { o.timeFormat = layout })
+}
+
+// SetUTC renders timestamps in UTC instead of local time
+func (lg *Logger) SetUTC(utc bool) {
+	lg.updateOpts(func(o *renderOptions) { o.utc = utc })
+}
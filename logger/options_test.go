@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	zone := time.FixedZone("CET", 3600)
+	now := time.Date(2026, 10, 14, 9, 5, 7, 123456789, zone)
+	tests := []struct {
+		name   string
+		layout string
+		utc    bool
+		want   string
+	}{
+		{"default", "", false, "2026/10/14 09:05:07"},
+		{"rfc3339", time.RFC3339, false, "2026-10-14T09:05:07+01:00"},
+		{"utc", time.RFC3339, true, "2026-10-14T08:05:07Z"},
+		{"millis", "15:04:05.000", false, "09:05:07.123"},
+		{"nanos utc", time.RFC3339Nano, true, "2026-10-14T08:05:07.123456789Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithPrintTime(true))
+			lg.SetClock(func() time.Time { return now })
+			lg.SetTimeFormat(tt.layout)
+			lg.SetUTC(tt.utc)
+			lg.Info("hi")
+			if got, want := buf.String(), "[TEST] "+tt.want+" [I]   hi\n"; got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestPrintTimeAndModule(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local)
+	tests := []struct {
+		time, module bool
+		want         string
+	}{
+		{true, true, "[TEST] 2026/10/14 09:05:07 [I]   hi\n"},
+		{true, false, "2026/10/14 09:05:07 [I]   hi\n"},
+		{false, true, "[TEST] [I]   hi\n"},
+		{false, false, "[I]   hi\n"},
+	}
+	for _, tt := range tests {
+		for _, order := range []string{"time first", "module first"} {
+			t.Run(fmt.Sprintf("time=%v module=%v %s", tt.time, tt.module, order), func(t *testing.T) {
+				lg, buf := newTestLogger(t)
+				lg.SetClock(func() time.Time { return now })
+				if order == "time first" {
+					lg.SetPrintTime(tt.time)
+					lg.SetPrintModule(tt.module)
+				} else {
+					lg.SetPrintModule(tt.module)
+					lg.SetPrintTime(tt.time)
+				}
+				lg.Info("hi")
+				if got := buf.String(); got != tt.want {
+					t.Fatalf("got %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+// Run with -race, toggling while messages are in flight
+func TestPrintTimeConcurrent(t *testing.T) {
+	lg := NewLogger("TEST", WithWriters(&syncBuffer{}))
+	defer lg.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			lg.SetPrintTime(i%2 == 0)
+			lg.SetPrintModule(i%3 == 0)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		lg.Info("x")
+	}
+	<-done
+}
@@ -0,0 +1,61 @@
+// Package otel correlates log messages with OpenTelemetry traces. It is a
+// separate package so the logger itself doesn't depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	"github.com/vizn3r/go-lib/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanField renders as the span ID and carries the span to EventHook
+type spanField struct {
+	span trace.Span
+}
+
+func (f spanField) String() string {
+	return f.span.SpanContext().SpanID().String()
+}
+
+func (f spanField) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// Extract is a logger.Extractor returning the trace_id and span_id of the
+// active span in ctx
+func Extract(ctx context.Context) map[string]any {
+	span := trace.SpanFromContext(ctx)
+	sc := span.SpanContext()
+	if !sc.IsValid() {
+		return nil
+	}
+	return map[string]any{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  spanField{span},
+	}
+}
+
+// EventHook records messages at minLevel and above as "log" events on the
+// span of loggers derived with WithContext
+func EventHook(minLevel logger.LogLevel) logger.Hook {
+	return func(level logger.LogLevel, module string, msg string, fields map[string]any) {
+		f, ok := fields["span_id"].(spanField)
+		if level < minLevel || !ok || !f.span.IsRecording() {
+			return
+		}
+		f.span.AddEvent("log", trace.WithAttributes(
+			attribute.String("log.severity", level.String()),
+			attribute.String("log.module", module),
+			attribute.String("log.message", msg),
+		))
+	}
+}
+
+// Install registers Extract for WithContext and adds an EventHook for
+// Error and above to lg
+func Install(lg *logger.Logger) logger.HookID {
+	logger.ContextExtractor(Extract)
+	return lg.AddHook(EventHook(logger.LevelError))
+}
@@ -0,0 +1,127 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/vizn3r/go-lib/logger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracer(t *testing.T) (trace.Tracer, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp.Tracer("test"), rec
+}
+
+func TestExtract(t *testing.T) {
+	tracer, _ := newTracer(t)
+	ctx, span := tracer.Start(context.Background(), "op")
+	defer span.End()
+	sc := span.SpanContext()
+
+	remote := trace.ContextWithRemoteSpanContext(context.Background(), sc)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{"no span", context.Background(), nil},
+		{"active span", ctx, map[string]string{"trace_id": sc.TraceID().String(), "span_id": sc.SpanID().String()}},
+		{"remote span", remote, map[string]string{"trace_id": sc.TraceID().String(), "span_id": sc.SpanID().String()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Extract(tt.ctx)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Extract = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if fmt.Sprint(got[k]) != v {
+					t.Errorf("%s = %v, want %s", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestWithContextFields(t *testing.T) {
+	logger.ContextExtractor(Extract)
+	tracer, _ := newTracer(t)
+	ctx, span := tracer.Start(context.Background(), "op")
+	defer span.End()
+	sc := span.SpanContext()
+
+	var buf bytes.Buffer
+	lg := logger.NewLogger("API", logger.WithWriters(&buf), logger.WithSync(true), logger.WithJSON())
+	defer lg.Close()
+	lg.WithContext(ctx).Info("handled")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if got["trace_id"] != sc.TraceID().String() || got["span_id"] != sc.SpanID().String() {
+		t.Fatalf("got %s, want trace_id %s and span_id %s", buf.String(), sc.TraceID(), sc.SpanID())
+	}
+}
+
+func TestEventHook(t *testing.T) {
+	logger.ContextExtractor(Extract)
+	tests := []struct {
+		name   string
+		log    func(lg *logger.Logger)
+		events []string // messages recorded on the span
+	}{
+		{"error", func(lg *logger.Logger) { lg.Error("failed") }, []string{"failed"}},
+		{"below the level", func(lg *logger.Logger) { lg.Info("fine"); lg.Warn("careful") }, nil},
+		{"each error", func(lg *logger.Logger) { lg.Error("a"); lg.Info("b"); lg.Error("c") }, []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, rec := newTracer(t)
+			ctx, span := tracer.Start(context.Background(), "op")
+
+			var buf bytes.Buffer
+			lg := logger.NewLogger("API", logger.WithWriters(&buf), logger.WithSync(true))
+			defer lg.Close()
+			lg.AddHook(EventHook(logger.LevelError))
+			tt.log(lg.WithContext(ctx))
+			lg.Error("no span") // not derived with WithContext
+			span.End()
+			lg.WithContext(ctx).Error("ended") // the span doesn't record anymore
+
+			ended := rec.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("%d spans ended, want 1", len(ended))
+			}
+			var got []string
+			for _, e := range ended[0].Events() {
+				attrs := map[string]string{}
+				for _, kv := range e.Attributes {
+					attrs[string(kv.Key)] = kv.Value.Emit()
+				}
+				if e.Name != "log" || attrs["log.severity"] != logger.LevelError.String() || attrs["log.module"] != "API" {
+					t.Errorf("event %s %v", e.Name, attrs)
+				}
+				got = append(got, attrs["log.message"])
+			}
+			if len(got) != len(tt.events) {
+				t.Fatalf("events %q, want %q", got, tt.events)
+			}
+			for i := range got {
+				if got[i] != tt.events[i] {
+					t.Fatalf("events %q, want %q", got, tt.events)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// ModulePaddingAuto pads module prefixes to the longest module name seen
+const ModulePaddingAuto = -1
+
+var (
+	modulePadding atomic.Int64 // 0 disables padding
+	moduleWidth   atomic.Int64 // longest module name, in runes
+)
+
+// SetModulePadding pads module names in text output to width characters so
+// messages of different modules line up. ModulePaddingAuto
+// tracks the longest module name created so far, 0 disables padding.
+func SetModulePadding(width int) {
+	modulePadding.Store(int64(width))
+}
+
+// registerModule records the length of a module name for auto padding
+func registerModule(module string) {
+	n := int64(utf8.RuneCountInString(module))
+	for {
+		cur := moduleWidth.Load()
+		if n <= cur || moduleWidth.CompareAndSwap(cur, n) {
+			return
+		}
+	}
+}
+
+// padModule returns the spaces that line module up with the others
+func padModule(module string) string {
+	width := int(modulePadding.Load())
+	if width == ModulePaddingAuto {
+		width = int(moduleWidth.Load())
+	}
+	n := utf8.RuneCountInString(module)
+	if n >= width {
+		return ""
+	}
+	return strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// keepPadding restores the package padding state when the test ends
+func keepPadding(t *testing.T) {
+	padding, width := modulePadding.Load(), moduleWidth.Load()
+	t.Cleanup(func() {
+		modulePadding.Store(padding)
+		moduleWidth.Store(width)
+	})
+}
+
+// messageColumns returns the visible column where "msg" starts on each line
+func messageColumns(out string) []int {
+	var cols []int
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		cols = append(cols, visibleWidth(line[:strings.Index(line, "msg")]))
+	}
+	return cols
+}
+
+func TestModulePadding(t *testing.T) {
+	keepPadding(t)
+	tests := []struct {
+		name  string
+		width int
+		want  []int
+	}{
+		{"off", 0, []int{13, 11, 18}},
+		{"fixed", 12, []int{21, 21, 21}},
+		{"narrower than a module", 5, []int{14, 14, 18}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetModulePadding(tt.width)
+			buf := &syncBuffer{}
+			for _, module := range []string{"HTTP", "DB", "SCHEDULER"} {
+				lg := NewLogger(module, WithWriters(buf), WithSync(true), WithColor(Blue), WithColorMode(ColorAlways), WithPrintTime(false))
+				lg.Info("msg")
+				lg.Close()
+			}
+			if got := messageColumns(buf.String()); !slices.Equal(got, tt.want) {
+				t.Fatalf("message columns = %v, want %v:\n%s", got, tt.want, stripANSI(buf.String()))
+			}
+		})
+	}
+}
+
+func TestModulePaddingAuto(t *testing.T) {
+	keepPadding(t)
+	SetModulePadding(ModulePaddingAuto)
+	moduleWidth.Store(0)
+
+	buf := &syncBuffer{}
+	opts := []Option{WithWriters(buf), WithSync(true), WithColor(Blue), WithColorMode(ColorAlways), WithPrintTime(false)}
+	http := NewLogger("HTTP", opts...)
+	db := NewLogger("DB", opts...)
+	defer http.Close()
+	defer db.Close()
+
+	http.Info("msg")
+	db.Info("msg")
+	sched := NewLogger("SCHEDULER", opts...)
+	defer sched.Close()
+	sched.Info("msg")
+	http.Info("msg")
+	db.Sub("Ω", Blue).Info("msg")
+
+	if got, want := messageColumns(buf.String()), []int{13, 13, 18, 18, 18}; !slices.Equal(got, want) {
+		t.Fatalf("message columns = %v, want %v:\n%s", got, want, stripANSI(buf.String()))
+	}
+}
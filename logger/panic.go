@@ -0,0 +1,62 @@
+package logger
+
+import "fmt"
+
+// Panic logs the message with a stack trace at LevelPanic, waits until it
+// has been written and then panics with the message
+func (lg *Logger) Panic(v ...any) {
+	lg.panicDepth(1, fmt.Sprint(v...))
+}
+
+// Panicf is Panic with a formatted message
+func (lg *Logger) Panicf(format string, v ...any) {
+	lg.panicDepth(1, fmt.Sprintf(format, v...))
+}
+
+// panicDepth logs msg and panics, depth is the number of frames between the
+// call site and panicDepth
+func (lg *Logger) panicDepth(depth int, msg string) {
+	if lg.enabled(LevelPanic) || lg.ring.Load() != nil {
+		m := lg.message(LevelPanic, msg)
+		skip := depth + 1 + int(lg.callerSkip.Load())
+		if lg.reportCaller.Load() {
+			m.caller = caller(skip)
+		}
+		m.stack = stack(skip, int(lg.stackDepth.Load()))
+		if lg.enabled(LevelPanic) {
+			lg.dispatch(m, true)
+		} else {
+			lg.record(m)
+		}
+	}
+	panic(msg)
+}
+
+// SetRepanic makes Recover panic again with the recovered value after
+// logging it
+func (lg *Logger) SetRepanic(repanic bool) {
+	lg.repanic.Store(repanic)
+}
+
+// Recover is meant to be deferred, it recovers a panic and logs its value
+// with the stack at error level
+func (lg *Logger) Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if lg.enabled(LevelError) || lg.ring.Load() != nil {
+		m := lg.message(LevelError, fmt.Sprint("panic: ", r))
+		// The frames include the runtime panic machinery and the origin
+		m.stack = stack(1, int(lg.stackDepth.Load()))
+		if lg.enabled(LevelError) {
+			lg.dispatch(m, true)
+		} else {
+			lg.record(m)
+		}
+	}
+	if lg.repanic.Load() {
+		panic(r)
+	}
+}
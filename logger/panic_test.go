@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// thisLine returns the line it was called from
+func thisLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestPanic(t *testing.T) {
+	tests := []struct {
+		name  string
+		panic func(lg *Logger, line *int)
+		want  string
+	}{
+		{"Panic", func(lg *Logger, line *int) { *line = thisLine(); lg.Panic("bad ", "state") }, "bad state"},
+		{"Panicf", func(lg *Logger, line *int) { *line = thisLine(); lg.Panicf("bad %d", 7) }, "bad 7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger("TEST", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+			defer lg.Close()
+			lg.SetReportCaller(true)
+
+			var out string
+			var line int
+			func() {
+				defer func() {
+					r := recover()
+					if r != tt.want {
+						t.Fatalf("panicked with %v, want %q", r, tt.want)
+					}
+					// written before panicking, not on Close
+					out = buf.String()
+				}()
+				tt.panic(lg, &line)
+			}()
+
+			at := "logger/panic_test.go:" + strconv.Itoa(line)
+			lines := strings.Split(out, "\n")
+			if !strings.HasSuffix(lines[0], " "+tt.want+" "+at) {
+				t.Fatalf("message line = %q, want the caller %s", lines[0], at)
+			}
+			if len(lines) < 2 || !strings.Contains(lines[1], ".TestPanic.func") || !strings.HasSuffix(lines[1], " "+at) {
+				t.Fatalf("stack doesn't start at the caller %s:\n%s", at, out)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		repanic bool
+	}{
+		{"recovered", false},
+		{"repanic", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetRepanic(tt.repanic)
+
+			done := make(chan any)
+			go func() {
+				defer func() { done <- recover() }()
+				defer lg.Recover()
+				panic("worker crashed")
+			}()
+			r := <-done
+
+			if tt.repanic != (r == "worker crashed") {
+				t.Fatalf("recovered %v after Recover", r)
+			}
+			first, stack, _ := strings.Cut(buf.String(), "\n")
+			if first != "[TEST] <E> ! panic: worker crashed" {
+				t.Fatalf("message line = %q", first)
+			}
+			if !strings.Contains(stack, "TestRecover.") {
+				t.Fatalf("stack doesn't reach the panicking goroutine:\n%s", buf)
+			}
+		})
+	}
+}
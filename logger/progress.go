@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Erases the current terminal line
+const clearLine = "\r\033[K"
+
+// Width of the progress bar in characters
+const progressBarWidth = 30
+
+// Progress is a single line redrawn in place on terminals. Other writers
+// get a plain message every 10%. Messages logged while a progress is
+// active are printed above the line.
+type Progress struct {
+	lg    *Logger
+	label string
+	total int64
+	start time.Time
+
+	cur   atomic.Int64
+	shown atomic.Int64 // last drawn percentage
+	step  atomic.Int64 // last 10% step written to other writers
+	done  atomic.Bool
+}
+
+// Progress starts a progress line, replacing any active one. A total of 0
+// or less shows only the count.
+func (lg *Logger) Progress(label string, total int) *Progress {
+	p := &Progress{lg: lg, label: label, total: int64(total), start: lg.now()}
+	p.shown.Store(-1)
+	lg.control(func() {
+		lg.progress = p
+		p.drawAll()
+	})
+	return p
+}
+
+// Increment advances the progress by n
+func (p *Progress) Increment(n int) {
+	p.update(p.cur.Add(int64(n)))
+}
+
+// Set moves the progress to n
+func (p *Progress) Set(n int) {
+	p.cur.Store(int64(n))
+	p.update(int64(n))
+}
+
+// Done removes the line and logs a summary with the elapsed time
+func (p *Progress) Done() {
+	if p.done.Swap(true) {
+		return
+	}
+	lg := p.lg
+	lg.control(func() {
+		if lg.progress == p {
+			lg.progress = nil
+			for _, s := range lg.sinks {
+				if s.tty && s.accepts(LevelInfo) {
+					s.write(LevelInfo, []byte(clearLine))
+				}
+			}
+		}
+	})
+	lg.logDepth(1, LevelInfo, fmt.Sprintf("%s done %s in %s", p.label, p.count(p.cur.Load()), humanDuration(lg.now().Sub(p.start))))
+}
+
+// update redraws at most once per percent and writes the 10% steps
+func (p *Progress) update(cur int64) {
+	if p.done.Load() {
+		return
+	}
+	pct := p.percent(cur)
+	if p.shown.Swap(pct) != pct {
+		p.lg.dispatch(logMessage{flush: true, ctrl: func() {
+			p.lg.wmu.Lock()
+			defer p.lg.wmu.Unlock()
+			if p.lg.progress == p {
+				p.drawAll()
+			}
+		}}, false)
+	}
+
+	if step := pct / 10; p.total > 0 && step > p.step.Load() && p.step.Swap(step) < step {
+		lg := p.lg
+		if lg.enabled(LevelInfo) {
+			m := lg.message(LevelInfo, fmt.Sprintf("%s %d%% %s", p.label, step*10, p.count(cur)))
+			m.progress = true
+			lg.emit(m)
+		}
+	}
+}
+
+// drawAll draws the line on every terminal, must be called with wmu held
+func (p *Progress) drawAll() {
+	for _, s := range p.lg.sinks {
+		p.draw(s)
+	}
+}
+
+// draw writes the line to s when it is a terminal, wmu must be held
+func (p *Progress) draw(s sink) {
+	if !s.tty || !s.accepts(LevelInfo) || s.formatOr(p.lg.opts.Load().format) != FormatText {
+		return
+	}
+	cur := p.cur.Load()
+
+	var b strings.Builder
+	b.WriteString(clearLine)
+	b.WriteString(p.label)
+	b.WriteByte(' ')
+	if p.total > 0 {
+		filled := int(min(cur, p.total) * progressBarWidth / p.total)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		if s.color {
+			fmt.Fprintf(&b, "[%s%s%s] %3d%% ", Green, bar, Reset, p.percent(cur))
+		} else {
+			fmt.Fprintf(&b, "[%s] %3d%% ", bar, p.percent(cur))
+		}
+	}
+	b.WriteString(p.count(cur))
+	s.write(LevelInfo, []byte(b.String()))
+}
+
+func (p *Progress) percent(cur int64) int64 {
+	if p.total <= 0 {
+		return cur
+	}
+	return min(100, max(0, cur*100/p.total))
+}
+
+func (p *Progress) count(cur int64) string {
+	if p.total <= 0 {
+		return fmt.Sprintf("%d", cur)
+	}
+	return fmt.Sprintf("%d/%d", cur, p.total)
+}
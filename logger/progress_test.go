@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTTY makes every sink of lg redraw progress lines like a terminal
+func fakeTTY(lg *Logger) {
+	lg.wmu.Lock()
+	defer lg.wmu.Unlock()
+	for i := range lg.sinks {
+		lg.sinks[i].tty = true
+	}
+}
+
+// bar is the progress line drawn for label with filled of the bar width
+func bar(label string, filled int, pct int, count string) string {
+	return fmt.Sprintf("%s%s [%s%s] %3d%% %s", clearLine, label,
+		strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), pct, count)
+}
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		tty  bool
+		run  func(lg *Logger, p *Progress)
+		want string
+	}{
+		{
+			name: "terminal redraw",
+			tty:  true,
+			run: func(lg *Logger, p *Progress) {
+				p.Increment(1)
+				p.Increment(0)
+				p.Set(4)
+			},
+			want: bar("up", 0, 0, "0/4") +
+				bar("up", 7, 25, "1/4") +
+				bar("up", 30, 100, "4/4") +
+				clearLine + "[I]   up done 4/4 in 1.00s\n",
+		},
+		{
+			name: "terminal interleaved message",
+			tty:  true,
+			run: func(lg *Logger, p *Progress) {
+				p.Increment(2)
+				lg.Info("mid")
+			},
+			want: bar("up", 0, 0, "0/4") +
+				bar("up", 15, 50, "2/4") +
+				clearLine + "[I]   mid\n" + bar("up", 15, 50, "2/4") +
+				clearLine + "[I]   up done 2/4 in 1.00s\n",
+		},
+		{
+			name: "plain steps",
+			run: func(lg *Logger, p *Progress) {
+				p.Set(1)
+				p.Set(3)
+				lg.Info("mid")
+				p.Increment(1)
+			},
+			want: "[I]   up 20% 1/4\n" +
+				"[I]   up 70% 3/4\n" +
+				"[I]   mid\n" +
+				"[I]   up 100% 4/4\n" +
+				"[I]   up done 4/4 in 1.00s\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			now := start
+			lg.SetClock(func() time.Time { return now })
+			if tt.tty {
+				fakeTTY(lg)
+			}
+
+			p := lg.Progress("up", 4)
+			tt.run(lg, p)
+			now = start.Add(time.Second)
+			p.Done()
+			p.Done()
+			p.Increment(1)
+
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressWithoutTotal(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetPrintModule(false)
+	fakeTTY(lg)
+
+	p := lg.Progress("rows", 0)
+	p.Increment(3)
+	p.Done()
+
+	got := buf.String()
+	want := clearLine + "rows 0" + clearLine + "rows 3" + clearLine
+	if !strings.HasPrefix(got, want) || !strings.Contains(got, "[I]   rows done 3 in ") {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestProgressAsyncInterleaving(t *testing.T) {
+	lg, buf := newTestLogger(t, WithSync(false))
+	lg.SetPrintModule(false)
+	fakeTTY(lg)
+
+	p := lg.Progress("up", 100)
+	go func() {
+		for i := 0; i < 100; i++ {
+			p.Increment(1)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		lg.Infof("line %d", i)
+	}
+	lg.Flush()
+
+	// every message starts on a cleared line and ends before the next redraw
+	out := buf.String()
+	for i := 0; i < 20; i++ {
+		line := fmt.Sprintf("[I]   line %d\n", i)
+		if !strings.Contains(out, clearLine+line+clearLine+"up [") {
+			t.Fatalf("%q spliced into the progress line:\n%q", line, out)
+		}
+	}
+}
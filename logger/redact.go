@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// redaction replaces matches of re, replacement may use $1 style groups
+type redaction struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Package wide redactions, an immutable slice swapped on update
+var (
+	redactions   atomic.Pointer[[]redaction]
+	redactionsMu sync.Mutex
+)
+
+// Built-in rules of EnableCommonRedactions
+var commonRedactions = []redaction{
+	{regexp.MustCompile(`(?i)(authorization\s*[:=]\s*)(bearer|basic|token)?\s*[^\s,;"']+`), "${1}${2} [REDACTED]"},
+	{regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9._~+/=-]+`), "Bearer [REDACTED]"},
+	{regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), "[REDACTED]"},
+	{regexp.MustCompile(`(?i)(aws_secret_access_key\s*[:=]\s*)[^\s,;"']+`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api_?key)=[^\s&"']+`), "${1}=[REDACTED]"},
+}
+
+// AddRedaction replaces every match of pattern in messages, error texts and
+// field values with replacement before they reach any writer or hook.
+// Rules apply to all loggers in the order they were added.
+func AddRedaction(pattern *regexp.Regexp, replacement string) {
+	addRedactions(redaction{pattern, replacement})
+}
+
+// EnableCommonRedactions adds rules for Authorization headers, bearer
+// tokens, AWS keys and password=... style parameters
+func EnableCommonRedactions() {
+	addRedactions(commonRedactions...)
+}
+
+// ClearRedactions removes every redaction rule
+func ClearRedactions() {
+	redactionsMu.Lock()
+	defer redactionsMu.Unlock()
+	redactions.Store(nil)
+}
+
+func addRedactions(add ...redaction) {
+	redactionsMu.Lock()
+	defer redactionsMu.Unlock()
+
+	var rules []redaction
+	if cur := redactions.Load(); cur != nil {
+		rules = append(rules, *cur...)
+	}
+	rules = append(rules, add...)
+	redactions.Store(&rules)
+}
+
+// redact applies the rules to the text of m, it is a no-op without rules
+func redact(m *logMessage) {
+	p := redactions.Load()
+	if p == nil {
+		return
+	}
+	rules := *p
+
+	m.msg = redactString(rules, m.msg)
+	if len(m.fields) > 0 {
+		fields := make([]Field, len(m.fields))
+		for i, f := range m.fields {
+			fields[i] = f
+			if s := fmt.Sprint(f.Value); redactString(rules, s) != s {
+				fields[i].Value = redactString(rules, s)
+			}
+		}
+		m.fields = fields
+	}
+	if m.err != nil {
+		m.err = redactError(rules, m.err)
+	}
+}
+
+func redactString(rules []redaction, s string) string {
+	for _, r := range rules {
+		s = r.re.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
+
+// redactPair redacts a key and value as they read in "key: value", so rules
+// that look at the name, like authorization, also hide the value
+func redactPair(rules []redaction, key, value string) (string, string) {
+	if len(rules) == 0 {
+		return key, value
+	}
+	if k, v, ok := strings.Cut(redactString(rules, key+": "+value), ": "); ok && k == key {
+		return k, v
+	}
+	return redactString(rules, key), redactString(rules, value)
+}
+
+// redactedError is a redacted copy of an error chain
+type redactedError struct {
+	text  string
+	cause error
+}
+
+func (e *redactedError) Error() string { return e.text }
+func (e *redactedError) Unwrap() error { return e.cause }
+
+func redactError(rules []redaction, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{redactString(rules, err.Error()), redactError(rules, errors.Unwrap(err))}
+}
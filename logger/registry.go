@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// Loggers registered through GetOrCreate, keyed by module
+var (
+	registry   = map[string]*Logger{}
+	registryMu sync.Mutex
+)
+
+// GetOrCreate returns the registered logger for module, creating and
+// registering it when there is none. Loggers made with New or NewLogger
+// are not registered.
+func GetOrCreate(module string, color Color, writers ...io.Writer) *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if lg, ok := registry[module]; ok {
+		return lg
+	}
+	lg := NewLogger(module, WithColor(color), WithWriters(writers...))
+	registry[module] = lg
+	return lg
+}
+
+// Lookup returns the registered logger for module or nil
+func Lookup(module string) *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return registry[module]
+}
+
+// CloseAll flushes and closes every registered logger, safe to call twice
+func CloseAll() {
+	registryMu.Lock()
+	loggers := make([]*Logger, 0, len(registry))
+	for _, lg := range registry {
+		loggers = append(loggers, lg)
+	}
+	registryMu.Unlock()
+
+	for _, lg := range loggers {
+		lg.Close()
+	}
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+)
+
+// unregister removes the modules from the registry when the test ends
+func unregister(t *testing.T, modules ...string) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		for _, m := range modules {
+			delete(registry, m)
+		}
+	})
+}
+
+func TestGetOrCreateRace(t *testing.T) {
+	unregister(t, "REG-RACE")
+	buf := &syncBuffer{}
+
+	var wg sync.WaitGroup
+	got := make([]*Logger, 16)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = GetOrCreate("REG-RACE", Blue, buf)
+		}()
+	}
+	wg.Wait()
+
+	for _, lg := range got[1:] {
+		if lg != got[0] {
+			t.Fatal("GetOrCreate returned more than one logger for a module")
+		}
+	}
+	if Lookup("REG-RACE") != got[0] {
+		t.Fatal("Lookup doesn't return the registered logger")
+	}
+	got[0].Close()
+}
+
+func TestRegistry(t *testing.T) {
+	unregister(t, "REG-A", "REG-B")
+	tests := []struct {
+		module string
+		want   bool // registered
+	}{
+		{"REG-A", true},
+		{"REG-B", true},
+		{"REG-NEW", false},
+		{"REG-MISSING", false},
+	}
+
+	a, b := &syncBuffer{}, &syncBuffer{}
+	GetOrCreate("REG-A", Blue, a).Info("a")
+	GetOrCreate("REG-B", Blue, b).Info("b")
+	NewLogger("REG-NEW", WithWriters(&syncBuffer{})).Close()
+	for _, tt := range tests {
+		if got := Lookup(tt.module) != nil; got != tt.want {
+			t.Errorf("Lookup(%q) registered = %v, want %v", tt.module, got, tt.want)
+		}
+	}
+
+	CloseAll()
+	CloseAll()
+	if a.String() == "" || b.String() == "" {
+		t.Fatalf("CloseAll didn't flush: %q, %q", a, b)
+	}
+}
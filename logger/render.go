@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buffers larger than this are not kept in the pool
+const maxPooledBuffer = 256 << 10
+
+var bufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func getBuffer() *bytes.Buffer {
+	return bufPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufPool.Put(b)
+}
+
+// continueLines starts every line after the first with a marker so the
+// text lines up under the message start at column col
+func continueLines(msg string, col int, color bool, style Color) string {
+	msg = strings.TrimSuffix(msg, "\n")
+	marker := strings.Repeat(" ", max(col-2, 0)) + "│ "
+	if color {
+		marker = string(Grey) + marker + string(Reset) + string(style)
+	}
+	return strings.ReplaceAll(msg, "\n", "\n"+marker)
+}
+
+// render appends a text line to b: prefix, time, level tag, message and
+// fields
+func (lg *core) render(b *bytes.Buffer, m logMessage, now time.Time, color bool) {
+	o := lg.opts.Load()
+	t := o.theme
+
+	if o.prefix != "" && !o.msgPrefix {
+		b.WriteString(o.prefix)
+	}
+	if o.printModule && o.layout == nil {
+		if color {
+			mc := m.color
+			if t.ModuleColor != "" {
+				mc = t.ModuleColor
+			}
+			b.WriteString(string(mc))
+		}
+		if t.ModuleBrackets {
+			b.WriteByte('[')
+		}
+		b.WriteString(m.module)
+		if t.ModuleBrackets {
+			b.WriteByte(']')
+		}
+		if color {
+			b.WriteString(string(Reset) + string(t.Prefix))
+		}
+		b.WriteString(padModule(m.module))
+		b.WriteByte(' ')
+	}
+	if o.printTime && o.layout == nil {
+		if color && !o.printModule {
+			b.WriteString(string(t.Prefix))
+		}
+		o.appendTimestamp(b, now)
+		b.WriteByte(' ')
+	}
+
+	msg := m.msg
+	if color && m.framed != "" {
+		msg = m.framed
+	}
+	if m.err != nil {
+		msg = errorText(m.msg, m.err)
+	}
+	if o.sanitize && m.level != LevelPrint {
+		msg = sanitizeLines(msg)
+	}
+	if !o.links(color) {
+		msg = unlink(msg)
+	}
+	style := m.style
+	if o.wholeLine && m.level >= LevelWarn {
+		style += t.Levels[m.level].Color
+	}
+	if color && (o.highlight || style != "") {
+		// Print messages continue in the prefix color, highlighted words
+		// have to restore it
+		base := Color("")
+		if m.level == LevelPrint && (o.printModule || o.printTime) {
+			base = t.Prefix
+		}
+		base += style
+		if o.highlight {
+			msg = colorString(lg.keywordSet(), msg, base) // color the content
+		}
+		if style != "" {
+			msg = string(style) + msg + string(Reset)
+		}
+	}
+	msg += lg.renderFields(m.fields, color)
+	if m.caller != "" {
+		if color {
+			msg += fmt.Sprintf(" %s%s%s", Grey, m.caller, Reset)
+		} else {
+			msg += " " + m.caller
+		}
+	}
+
+	if o.layout != nil {
+		lg.renderLayout(b, o, m, now, color)
+	} else if tag, ok := t.Levels[m.level]; ok {
+		if color {
+			if tag.Color == "" {
+				// don't continue in the prefix color
+				tag.Color = Reset
+			}
+			fmt.Fprintf(b, "%s%s%s", tag.Color, tag.Text, Reset)
+		} else {
+			b.WriteString(tag.Text)
+		}
+	}
+	if m.indent > 0 {
+		b.WriteString(strings.Repeat("  ", m.indent))
+	}
+	if o.prefix != "" && o.msgPrefix {
+		b.WriteString(o.prefix)
+	}
+	if m.level != LevelPrint && strings.Contains(msg, "\n") {
+		msg = continueLines(msg, visibleWidth(b.String()), color, style)
+	}
+	b.WriteString(msg)
+
+	for _, f := range m.stack {
+		if color {
+			fmt.Fprintf(b, "\n%s    %s%s", Grey, f, Reset)
+		} else {
+			b.WriteString("\n    " + f)
+		}
+	}
+
+	if !strings.HasSuffix(msg, "\n") || len(m.stack) > 0 {
+		b.WriteByte('\n')
+	}
+}
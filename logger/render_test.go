@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContinuationLines(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		log  func(lg *Logger)
+		want string
+	}{
+		{"three lines", nil, func(lg *Logger) { lg.Info("a\nb\nc") },
+			"[TEST] [I]   a\n           │ b\n           │ c\n"},
+		{"trailing newline", nil, func(lg *Logger) { lg.Info("a\nb\n") },
+			"[TEST] [I]   a\n           │ b\n"},
+		{"fields after the last line", nil, func(lg *Logger) { lg.WithField("k", 1).Warn("a\nb") },
+			"[TEST] [W] ? a\n           │ b k=1\n"},
+		{"with time", []Option{WithPrintTime(true)}, func(lg *Logger) { lg.Info("a\nb") },
+			"[TEST] 2026/10/14 09:05:07 [I]   a\n                               │ b\n"},
+		{"colors don't count", []Option{WithColorMode(ColorAlways), WithColor(Blue)}, func(lg *Logger) {
+			lg.SetHighlighting(false)
+			lg.Warn("a\nb")
+		}, "\033[34m[TEST]\033[0m\033[90m \033[33m[W] ? \033[0ma\n\033[90m           │ \033[0mb\n"},
+		{"print isn't marked", nil, func(lg *Logger) { lg.Print("a\nb") },
+			"[TEST] a\nb\n"},
+		{"control characters escaped per line", nil, func(lg *Logger) { lg.Info("a\x1b[2J\rx\nb") },
+			"[TEST] [I]   a\\rx\n           │ b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, tt.opts...)
+			lg.SetClock(func() time.Time { return time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local) })
+			tt.log(lg)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContinuationLinesInJSON(t *testing.T) {
+	lg, buf := newTestLogger(t, WithJSON())
+	lg.Info("a\nb\nc")
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("JSON output isn't a single line: %q", line)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["msg"] != "a\nb\nc" {
+		t.Fatalf("msg = %q, want the lines unmarked", got["msg"])
+	}
+}
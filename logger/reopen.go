@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Reopener is a writer that can reopen its destination, e.g. after
+// logrotate moved the file away
+type Reopener interface {
+	Reopen() error
+}
+
+// FileWriter appends to a file and reopens the path on Reopen
+type FileWriter struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileWriter opens path for appending, creating it if needed
+func NewFileWriter(path string) (*FileWriter, error) {
+	f, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileWriter{path: path, f: f}, nil
+}
+
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	return w.f.Write(p)
+}
+
+// Reopen closes the current file and opens the path again, writes in
+// flight finish on the old file
+func (w *FileWriter) Reopen() error {
+	f, err := openAppend(w.path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	old := w.f
+	w.f = f
+	w.mu.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+func (w *FileWriter) managed() {}
+
+// HandleSIGHUP calls Reopen on every writer when the process receives
+// SIGHUP, as logrotate expects. The returned function removes the handler.
+func HandleSIGHUP(writers ...Reopener) func() {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				for _, w := range writers {
+					w.Reopen()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
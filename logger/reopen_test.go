@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFileWriterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg := NewLogger("TEST", WithWriters(w), WithSync(true), WithNoColor(), WithPrintTime(false))
+	defer lg.Close()
+
+	lg.Info("before")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("moved")
+	if err := w.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("after")
+
+	if got := readFile(t, path+".1"); got != "[TEST] [I]   before\n[TEST] [I]   moved\n" {
+		t.Fatalf("rotated file = %q", got)
+	}
+	if got := readFile(t, path); got != "[TEST] [I]   after\n" {
+		t.Fatalf("reopened file = %q", got)
+	}
+}
+
+func TestFileWriterReopenConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if _, err := fmt.Fprintf(w, "%d %d\n", g, i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err := w.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	if n := strings.Count(readFile(t, path), "\n"); n != 400 {
+		t.Fatalf("%d of 400 lines written", n)
+	}
+}
+
+func TestFileWriterClosed(t *testing.T) {
+	w, err := NewFileWriter(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if _, err := w.Write([]byte("x")); err != os.ErrClosed {
+		t.Fatalf("write after Close = %v, want os.ErrClosed", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close = %v", err)
+	}
+}
+
+func TestHandleSIGHUP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGHUP on windows")
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	stop := HandleSIGHUP(w)
+	defer stop()
+
+	os.Rename(path, path+".1")
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("file not reopened after SIGHUP")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ringBuffer keeps the last entries as plain rendered lines
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+func (r *ringBuffer) add(line []byte) {
+	r.mu.Lock()
+	r.entries[r.next] = line
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+	r.mu.Unlock()
+}
+
+// snapshot returns the entries from oldest to newest
+func (r *ringBuffer) snapshot() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([][]byte(nil), r.entries[:r.next]...)
+	}
+	out := make([][]byte, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// record keeps a plain rendering of m in the ring buffer if there is one.
+// Banner frames and messages whose arguments wait for the consumer are left
+// out.
+func (lg *core) record(m logMessage) {
+	ring := lg.ring.Load()
+	if ring == nil || m.flush || m.args != nil || m.msg == "" && m.framed != "" {
+		return
+	}
+	now := m.time
+	if now.IsZero() {
+		now = lg.now()
+	}
+	redact(&m)
+	b := getBuffer()
+	lg.render(b, m, now, false)
+	ring.add(bytes.Clone(b.Bytes()))
+	putBuffer(b)
+}
+
+// EnableRingBuffer keeps the last n messages of any level, including those
+// below the current level, for DumpRecent. n of 0 or less disables it.
+// While enabled, messages below the level are formatted too.
+func (lg *Logger) EnableRingBuffer(n int) {
+	if n <= 0 {
+		lg.ring.Store(nil)
+		return
+	}
+	lg.ring.Store(&ringBuffer{entries: make([][]byte, n)})
+}
+
+// DumpRecent writes the buffered messages without colors, oldest first
+func (lg *Logger) DumpRecent(w io.Writer) error {
+	ring := lg.ring.Load()
+	if ring == nil {
+		return nil
+	}
+	for _, line := range ring.snapshot() {
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpRecentOnPanic is meant to be deferred, on a panic it writes the
+// buffered messages to w and panics again
+func (lg *Logger) DumpRecentOnPanic(w io.Writer) {
+	if r := recover(); r != nil {
+		fmt.Fprintf(w, "panic: %v, recent log messages:\n", r)
+		lg.DumpRecent(w)
+		panic(r)
+	}
+}
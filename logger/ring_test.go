@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		logged int
+		want   []int // numbers of the dumped messages
+	}{
+		{"partly filled", 5, 3, []int{0, 1, 2}},
+		{"exactly full", 3, 3, []int{0, 1, 2}},
+		{"overflowed", 3, 8, []int{5, 6, 7}},
+		{"disabled", 0, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, _ := newTestLogger(t, WithLevel(LevelError), WithColorMode(ColorAlways))
+			lg.EnableRingBuffer(tt.size)
+			for i := 0; i < tt.logged; i++ {
+				// below the level, still kept
+				lg.Debugf("GET %d", i)
+			}
+
+			var dump bytes.Buffer
+			if err := lg.DumpRecent(&dump); err != nil {
+				t.Fatal(err)
+			}
+			var want strings.Builder
+			for _, n := range tt.want {
+				fmt.Fprintf(&want, "[TEST] [D]   GET %d\n", n)
+			}
+			if dump.String() != want.String() {
+				t.Fatalf("dump:\n%q\nwant:\n%q", dump.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestDumpRecentOnPanic(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	lg.EnableRingBuffer(2)
+	lg.Info("one")
+	lg.Info("two")
+
+	var dump bytes.Buffer
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("panic value %v wasn't passed on", r)
+			}
+		}()
+		defer lg.DumpRecentOnPanic(&dump)
+		panic("boom")
+	}()
+
+	want := "panic: boom, recent log messages:\n[TEST] [I]   one\n[TEST] [I]   two\n"
+	if dump.String() != want {
+		t.Fatalf("dump = %q, want %q", dump.String(), want)
+	}
+}
+
+func TestRingBufferPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(lg *Logger)
+		want string
+	}{
+		{"Err", func(lg *Logger) { lg.Err(errors.New("disk full"), "save") }, "<E> ! save: disk full"},
+		{"Panic", func(lg *Logger) {
+			defer func() { recover() }()
+			lg.Panic("bad state")
+		}, "<P>!!! bad state"},
+		{"Recover", func(lg *Logger) {
+			defer lg.Recover()
+			panic("worker crashed")
+		}, "<E> ! panic: worker crashed"},
+		{"RecoverHandler", func(lg *Logger) {
+			h := RecoverHandler(lg, http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("handler crashed") }))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+		}, "<E> ! panic in GET /x: handler crashed"},
+		{"LogTo", func(lg *Logger) { lg.LogTo(io.Discard, LevelWarn, "teed") }, "[W] ? teed"},
+		{"slog", func(lg *Logger) { slog.New(NewSlogHandler(lg)).Warn("from slog", "k", 1) }, "[W] ? from slog k=1"},
+	}
+	for _, tt := range tests {
+		for _, level := range []LogLevel{LevelTrace, LevelDisabled} {
+			t.Run(tt.name+"/"+level.String(), func(t *testing.T) {
+				lg, _ := newTestLogger(t, WithLevel(level))
+				lg.EnableRingBuffer(4)
+				tt.log(lg)
+
+				var dump bytes.Buffer
+				if err := lg.DumpRecent(&dump); err != nil {
+					t.Fatal(err)
+				}
+				if first, _, _ := strings.Cut(dump.String(), "\n"); first != "[TEST] "+tt.want {
+					t.Fatalf("dump:\n%s\nwant first line %q", dump.String(), "[TEST] "+tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timestamp suffix of rotated files, sorts chronologically
+const rotateLayout = "20060102T150405.000"
+
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+	move       func(src, dst string) error
+
+	mu     sync.Mutex
+	f      *os.File // nil after a failed rotation, reopened by Write
+	size   int64
+	closed bool
+
+	cleanupMu sync.Mutex // serializes compressing and pruning backups
+}
+
+// RotatingFile returns a writer appending to path that renames the file
+// with a timestamp suffix once it would exceed maxSizeMB, keeping at most
+// maxBackups old files (0 keeps all), gzipped when compress is set. When a
+// rotation fails writing continues in path. Closing the Logger it is passed
+// to closes the file.
+func RotatingFile(path string, maxSizeMB int, maxBackups int, compress bool) (io.WriteCloser, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+		compress:   compress,
+		now:        time.Now,
+		move:       moveFile,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// Write writes p, rotating first when it wouldn't fit. A backup that
+// couldn't be compressed or pruned is reported after p was written.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	n, backup, err := r.write(p)
+	if backup != "" {
+		err = errors.Join(err, r.cleanup(backup))
+	}
+	return n, err
+}
+
+// write writes p under mu, backup is the file moved aside by a rotation
+func (r *rotatingFile) write(p []byte) (n int, backup string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, "", os.ErrClosed
+	}
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, "", err
+		}
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if backup, err = r.rotate(); err != nil {
+			return 0, "", err
+		}
+	}
+	n, err = r.f.Write(p)
+	r.size += int64(n)
+	return n, backup, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *rotatingFile) managed() {}
+
+// rotate moves the current file aside and opens a fresh one, must be
+// called with mu held. When the file can't be moved it is reopened, so
+// the next write appends to it again.
+func (r *rotatingFile) rotate() (backup string, err error) {
+	err = r.f.Close()
+	r.f = nil
+	if err != nil {
+		return "", errors.Join(err, r.open())
+	}
+
+	backup, err = r.backupPath()
+	if err == nil {
+		err = r.move(r.path, backup)
+	}
+	if err != nil {
+		return "", errors.Join(err, r.open())
+	}
+	if err := r.open(); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// cleanup compresses backup and removes the backups beyond maxBackups. It
+// runs without mu, so a slow gzip doesn't hold up other writes.
+func (r *rotatingFile) cleanup(backup string) error {
+	r.cleanupMu.Lock()
+	defer r.cleanupMu.Unlock()
+
+	var err error
+	if r.compress {
+		if err = gzipFile(backup); err != nil {
+			err = fmt.Errorf("couldn't compress %s: %w", backup, err)
+		}
+	}
+	if perr := r.prune(); perr != nil {
+		err = errors.Join(err, fmt.Errorf("couldn't remove old backups: %w", perr))
+	}
+	return err
+}
+
+// backupPath returns the name of the next backup, rotations within the
+// same millisecond get a counter like "app-20060102T150405.000-1.log"
+func (r *rotatingFile) backupPath() (string, error) {
+	backups, err := r.backups()
+	if err != nil {
+		return "", err
+	}
+
+	stamp := r.now().Format(rotateLayout)
+	n := -1
+	for _, b := range backups {
+		if b.stamp == stamp {
+			n = max(n, b.n)
+		}
+	}
+	ext := filepath.Ext(r.path)
+	base := strings.TrimSuffix(r.path, ext) + "-" + stamp
+	if n < 0 {
+		return base + ext, nil
+	}
+	return base + "-" + strconv.Itoa(n+1) + ext, nil
+}
+
+type backupFile struct {
+	name  string
+	stamp string
+	n     int // counter of backups made in the same millisecond
+}
+
+// backups returns the backups of the file from oldest to newest
+func (r *rotatingFile) backups() ([]backupFile, error) {
+	ext := filepath.Ext(r.path)
+	prefix := filepath.Base(strings.TrimSuffix(r.path, ext)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		stamp, ok := strings.CutPrefix(strings.TrimSuffix(name, ".gz"), prefix)
+		if !ok || !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		stamp = strings.TrimSuffix(stamp, ext)
+		n := 0
+		if len(stamp) > len(rotateLayout) {
+			count, ok := strings.CutPrefix(stamp[len(rotateLayout):], "-")
+			if n, err = strconv.Atoi(count); !ok || err != nil {
+				continue
+			}
+			stamp = stamp[:len(rotateLayout)]
+		}
+		if _, err := time.Parse(rotateLayout, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{name, stamp, n})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].stamp != backups[j].stamp {
+			return backups[i].stamp < backups[j].stamp
+		}
+		return backups[i].n < backups[j].n
+	})
+	return backups, nil
+}
+
+// prune removes the oldest backups beyond maxBackups
+func (r *rotatingFile) prune() error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := r.backups()
+	if err != nil || len(backups) <= r.maxBackups {
+		return err
+	}
+	for _, b := range backups[:len(backups)-r.maxBackups] {
+		if err := os.Remove(filepath.Join(filepath.Dir(r.path), b.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveFile renames src to dst, copying when they are on different devices
+func moveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !crossDevice(err) {
+		return err
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// gzipFile replaces path with path.gz
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
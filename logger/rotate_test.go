@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRotatingFile returns a rotatingFile in a temporary directory that
+// rotates after maxSize bytes, with a clock that doesn't move
+func newRotatingFile(t *testing.T, maxSize int64, maxBackups int, compress bool) *rotatingFile {
+	t.Helper()
+
+	w, err := RotatingFile(filepath.Join(t.TempDir(), "app.log"), 1, maxBackups, compress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+	r := w.(*rotatingFile)
+	r.maxSize = maxSize
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackups int
+		compress   bool
+		writes     int
+		want       []string
+	}{
+		{
+			name:   "no rotation",
+			writes: 1,
+			want:   []string{"app.log"},
+		},
+		{
+			name:   "same millisecond",
+			writes: 4,
+			want: []string{
+				"app-20260102T030405.006-1.log",
+				"app-20260102T030405.006-2.log",
+				"app-20260102T030405.006.log",
+				"app.log",
+			},
+		},
+		{
+			name:       "max backups",
+			maxBackups: 2,
+			writes:     5,
+			want: []string{
+				"app-20260102T030405.006-2.log",
+				"app-20260102T030405.006-3.log",
+				"app.log",
+			},
+		},
+		{
+			name:       "max backups gzipped",
+			maxBackups: 1,
+			compress:   true,
+			writes:     3,
+			want: []string{
+				"app-20260102T030405.006-1.log.gz",
+				"app.log",
+			},
+		},
+		{
+			name:     "compress",
+			compress: true,
+			writes:   3,
+			want: []string{
+				"app-20260102T030405.006-1.log.gz",
+				"app-20260102T030405.006.log.gz",
+				"app.log",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRotatingFile(t, 10, tt.maxBackups, tt.compress)
+			for i := 0; i < tt.writes; i++ {
+				if _, err := r.Write([]byte("0123456789")); err != nil {
+					t.Fatalf("write %d: %v", i, err)
+				}
+			}
+
+			got := dirNames(t, filepath.Dir(r.path))
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingFileMoveFailure(t *testing.T) {
+	r := newRotatingFile(t, 10, 0, false)
+	r.move = func(src, dst string) error { return errors.New("no space left") }
+
+	if _, err := r.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("abc")); err == nil {
+		t.Fatal("failed rotation wasn't reported")
+	}
+
+	if r.f == nil {
+		t.Fatal("file wasn't reopened after the failed rotation")
+	}
+
+	r.move = moveFile
+	if _, err := r.Write([]byte("def")); err != nil {
+		t.Fatalf("writing after a failed rotation: %v", err)
+	}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "def" {
+		t.Fatalf("current file = %q, want the line written after rotating", data)
+	}
+	backup, err := os.ReadFile(filepath.Join(filepath.Dir(r.path), "app-20260102T030405.006.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != "0123456789" {
+		t.Fatalf("backup = %q, want the line written before the failure", backup)
+	}
+}
+
+func TestRotatingFileCleanupFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackups int
+		compress   bool
+		block      func(r *rotatingFile) error // makes the cleanup fail
+	}{
+		{"compress", 0, true, func(r *rotatingFile) error {
+			r.move = func(src, dst string) error {
+				// a directory where the gzipped backup goes
+				return errors.Join(moveFile(src, dst), os.Mkdir(dst+".gz", 0o755))
+			}
+			return nil
+		}},
+		{"prune", 1, false, func(r *rotatingFile) error {
+			// an old backup that can't be removed
+			return os.MkdirAll(filepath.Join(filepath.Dir(r.path), "app-20250101T000000.000.log", "keep"), 0o755)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRotatingFile(t, 10, tt.maxBackups, tt.compress)
+			dir := filepath.Dir(r.path)
+			if err := tt.block(r); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := r.Write([]byte("0123456789")); err != nil {
+				t.Fatal(err)
+			}
+			n, err := r.Write([]byte("abc"))
+			if err == nil {
+				t.Fatal("failed cleanup wasn't reported")
+			}
+			if n != 3 {
+				t.Fatalf("wrote %d bytes, want the line written despite %v", n, err)
+			}
+			data, err := os.ReadFile(r.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "abc" {
+				t.Fatalf("current file = %q, want the line written after rotating", data)
+			}
+			backup, err := os.ReadFile(filepath.Join(dir, "app-20260102T030405.006.log"))
+			if err != nil || string(backup) != "0123456789" {
+				t.Fatalf("backup = %q, %v", backup, err)
+			}
+		})
+	}
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	r := newRotatingFile(t, 10, 0, false)
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("write after Close = %v, want os.ErrClosed", err)
+	}
+}
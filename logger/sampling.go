@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Upper bound of distinct messages tracked by the sampler
+const maxSampleEntries = 1024
+
+type sampleKey struct {
+	level LogLevel
+	msg   string
+}
+
+type sampleEntry struct {
+	start      time.Time
+	count      int
+	suppressed int
+	last       logMessage
+}
+
+// sampler lets through the first initial copies of a message per window
+// and then every thereafter-th one
+type sampler struct {
+	initial    int
+	thereafter int
+	window     time.Duration
+
+	mu        sync.Mutex
+	entries   map[sampleKey]*sampleEntry
+	lastSweep time.Time
+	timer     *time.Timer // writes the summaries of ended windows
+}
+
+// SetSampling logs only the first initial identical messages (same level
+// and text) within window, then every thereafter-th. When the window ends
+// a summary with the number of suppressed copies is written, even if no
+// other message arrives, and pending summaries are written on Close. An
+// initial of 0 or less turns sampling off.
+func (lg *Logger) SetSampling(initial int, thereafter int, window time.Duration) {
+	if initial <= 0 || window <= 0 {
+		lg.sampler.Store(nil)
+		return
+	}
+	lg.sampler.Store(&sampler{
+		initial:    initial,
+		thereafter: thereafter,
+		window:     window,
+		entries:    map[sampleKey]*sampleEntry{},
+	})
+}
+
+// sample reports whether m should be written, Fatal and Panic always are
+func (lg *core) sample(m logMessage) bool {
+	s := lg.sampler.Load()
+	if s == nil || m.level >= LevelFatal {
+		return true
+	}
+
+	now := lg.now()
+	s.mu.Lock()
+	var summaries []sampleEntry
+	if now.Sub(s.lastSweep) >= s.window {
+		s.lastSweep = now
+		summaries = s.sweep(now, false)
+	}
+
+	key := sampleKey{m.level, m.msg}
+	e := s.entries[key]
+	if e == nil || now.Sub(e.start) >= s.window {
+		if e != nil && e.suppressed > 0 {
+			summaries = append(summaries, *e)
+		}
+		if e == nil && len(s.entries) >= maxSampleEntries {
+			summaries = append(summaries, s.sweep(now, true)...)
+		}
+		e = &sampleEntry{start: now}
+		s.entries[key] = e
+	}
+	e.count++
+	e.last = m
+
+	pass := e.count <= s.initial ||
+		(s.thereafter > 0 && (e.count-s.initial)%s.thereafter == 0)
+	if !pass {
+		e.suppressed++
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.window-now.Sub(e.start), func() { lg.expireSamples(s) })
+		}
+	}
+	s.mu.Unlock()
+
+	lg.printSummaries(summaries)
+	return pass
+}
+
+// expireSamples runs on the consumer to write the summaries of windows
+// that ended without another copy arriving
+func (lg *core) expireSamples(s *sampler) {
+	lg.dispatch(logMessage{flush: true, ctrl: func() { lg.flushSamples(s, false) }}, false)
+}
+
+// flushSamples writes the summaries of ended windows, or of all of them
+// with force, and schedules the next one
+func (lg *core) flushSamples(s *sampler, force bool) {
+	now := lg.now()
+	s.mu.Lock()
+	summaries := s.sweep(now, force)
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !force {
+		next := time.Duration(-1)
+		for _, e := range s.entries {
+			if d := s.window - now.Sub(e.start); e.suppressed > 0 && (next < 0 || d < next) {
+				next = max(d, 0)
+			}
+		}
+		if next >= 0 {
+			s.timer = time.AfterFunc(next, func() { lg.expireSamples(s) })
+		}
+	}
+	s.mu.Unlock()
+
+	lg.printSummaries(summaries)
+}
+
+func (lg *core) printSummaries(summaries []sampleEntry) {
+	for _, e := range summaries {
+		lg.printer(lg.internalMessage(e.last, e.last.level,
+			fmt.Sprintf("last message repeated %d times: %s", e.suppressed, e.last.msg)))
+	}
+}
+
+// sweep removes entries whose window ended, or all of them with force,
+// returning those that suppressed messages. Must be called with mu held.
+func (s *sampler) sweep(now time.Time, force bool) []sampleEntry {
+	var out []sampleEntry
+	for k, e := range s.entries {
+		if force || now.Sub(e.start) >= s.window {
+			if e.suppressed > 0 {
+				out = append(out, *e)
+			}
+			delete(s.entries, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	tests := []struct {
+		name                string
+		initial, thereafter int
+		n                   int
+		want                int // copies written
+	}{
+		{"initial only", 3, 0, 10, 3},
+		{"thereafter", 2, 3, 10, 4},
+		{"below initial", 5, 0, 4, 4},
+		{"every copy", 1, 1, 5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetSampling(tt.initial, tt.thereafter, time.Hour)
+			for i := 0; i < tt.n; i++ {
+				lg.Info("same")
+			}
+			if got := strings.Count(buf.String(), "same\n"); got != tt.want {
+				t.Fatalf("wrote %d copies, want %d:\n%s", got, tt.want, buf)
+			}
+		})
+	}
+}
+
+func TestSamplingSummaryAfterSilence(t *testing.T) {
+	for _, sync := range []bool{true, false} {
+		buf := &syncBuffer{}
+		lg := NewLogger("TEST", WithWriters(buf), WithSync(sync), WithNoColor(), WithPrintTime(false))
+		lg.SetSampling(1, 0, 20*time.Millisecond)
+		for i := 0; i < 5; i++ {
+			lg.Info("burst")
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for !strings.Contains(buf.String(), "last message repeated 4 times: burst") {
+			if time.Now().After(deadline) {
+				t.Fatalf("sync %v: no summary after the window ended:\n%s", sync, buf)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		lg.Close()
+	}
+}
+
+func TestSamplingSummaryOnClose(t *testing.T) {
+	for _, sync := range []bool{true, false} {
+		buf := &syncBuffer{}
+		lg := NewLogger("TEST", WithWriters(buf), WithSync(sync), WithNoColor(), WithPrintTime(false))
+		lg.SetSampling(1, 0, time.Hour)
+		for i := 0; i < 3; i++ {
+			lg.Warn("burst")
+		}
+		lg.Close()
+
+		if !strings.Contains(buf.String(), "last message repeated 2 times: burst") {
+			t.Fatalf("sync %v: no summary on close:\n%s", sync, buf)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// serialWriter makes every Write atomic across the loggers sharing it
+type serialWriter struct {
+	io.Writer
+	mu *sync.Mutex
+}
+
+func (w *serialWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Writer.Write(p)
+}
+
+// Locks of the standard streams, every logger writing to them shares these
+var stdoutLock, stderrLock sync.Mutex
+
+// Serialize wraps w so lines written by several loggers never interleave,
+// pass the returned writer to each of them. The lock lives in the wrapper
+// and is gone with it. os.Stdout and os.Stderr are serialized without it.
+func Serialize(w io.Writer) io.Writer {
+	if s, ok := w.(*serialWriter); ok {
+		return s
+	}
+	mu := stdLock(w)
+	if mu == nil {
+		mu = new(sync.Mutex)
+	}
+	return &serialWriter{Writer: w, mu: mu}
+}
+
+// stdLock returns the shared lock of the standard streams, nil for any
+// other writer
+func stdLock(w io.Writer) *sync.Mutex {
+	f, ok := w.(*os.File)
+	switch {
+	case ok && f == os.Stdout:
+		return &stdoutLock
+	case ok && f == os.Stderr:
+		return &stderrLock
+	}
+	return nil
+}
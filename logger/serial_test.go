@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tornWriter writes each call in two halves, interleaving concurrent
+// lines unless writes are serialized
+type tornWriter struct {
+	mu  sync.Mutex
+	out strings.Builder
+}
+
+func (w *tornWriter) Write(p []byte) (int, error) {
+	half := len(p) / 2
+	w.mu.Lock()
+	w.out.Write(p[:half])
+	w.mu.Unlock()
+	w.mu.Lock()
+	w.out.Write(p[half:])
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+func TestSerialize(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(w io.Writer) io.Writer
+	}{
+		{"once", Serialize},
+		{"twice", func(w io.Writer) io.Writer { return Serialize(Serialize(w)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &tornWriter{}
+			s := tt.wrap(w)
+			var wg sync.WaitGroup
+			for _, module := range []string{"A", "B"} {
+				lg := NewLogger(module, WithWriters(s), WithSync(true), WithNoColor(), WithPrintTime(false))
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer lg.Close()
+					for i := 0; i < 200; i++ {
+						lg.Info("a line long enough to be torn apart")
+					}
+				}()
+			}
+			wg.Wait()
+
+			lines := strings.Split(strings.TrimSuffix(w.out.String(), "\n"), "\n")
+			if len(lines) != 400 {
+				t.Fatalf("%d lines, want 400", len(lines))
+			}
+			for _, l := range lines {
+				if !strings.HasSuffix(l, "[I]   a line long enough to be torn apart") {
+					t.Fatalf("interleaved line %q", l)
+				}
+			}
+		})
+	}
+}
+
+func TestSerializeLocks(t *testing.T) {
+	w := &tornWriter{}
+	s := Serialize(w).(*serialWriter)
+	if Serialize(s) != s {
+		t.Fatal("Serialize wrapped a serialized writer again")
+	}
+	if other := Serialize(w).(*serialWriter); other.mu == s.mu {
+		t.Fatal("wrappers of a plain writer share a package wide lock")
+	}
+	if Serialize(os.Stdout).(*serialWriter).mu != stdLock(os.Stdout) {
+		t.Fatal("stdout wrapper doesn't use the stdout lock")
+	}
+	if stdLock(os.Stdout) == stdLock(os.Stderr) || stdLock(w) != nil {
+		t.Fatal("wrong standard stream locks")
+	}
+}
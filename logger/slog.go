@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+// slogHandler implements slog.Handler on top of a Logger
+type slogHandler struct {
+	lg     *Logger
+	fields []Field // accumulated through WithAttrs
+	group  string  // key prefix from WithGroup, ends with a dot
+}
+
+// NewSlogHandler returns a slog.Handler writing through lg, attributes
+// become fields and groups prefix their keys with "group."
+func NewSlogHandler(lg *Logger) slog.Handler {
+	return &slogHandler{lg: lg}
+}
+
+// slogLevel maps slog levels onto the logger levels
+func slogLevel(l slog.Level) LogLevel {
+	switch {
+	case l < slog.LevelDebug:
+		return LevelTrace
+	case l < slog.LevelInfo:
+		return LevelDebug
+	case l < slog.LevelWarn:
+		return LevelInfo
+	case l < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	// Records below the level still go to the ring buffer
+	return h.lg.enabled(slogLevel(l)) || h.lg.ring.Load() != nil
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.group, a)
+		return true
+	})
+	lg := h.lg.with(mergeFields(h.fields, fields))
+	m := lg.message(slogLevel(r.Level), r.Message)
+	if !r.Time.IsZero() {
+		m.time = r.Time
+	}
+	if lg.reportCaller.Load() && r.PC != 0 {
+		// The record knows the call site, frames here are slog internals
+		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		m.caller = shortPath(f.File) + ":" + strconv.Itoa(f.Line)
+	}
+	if !lg.enabled(m.level) {
+		lg.record(m)
+		return nil
+	}
+	lg.emit(m)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]Field, 0, len(attrs))
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.group, a)
+	}
+	child := *h
+	child.fields = mergeFields(h.fields, fields)
+	return &child
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.group = h.group + name + "."
+	return &child
+}
+
+// appendAttr flattens a, group attributes are expanded with their key as
+// a prefix and empty attributes are skipped as slog requires
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
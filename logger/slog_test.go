@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{"attrs", func(l *slog.Logger) { l.Info("hi", "k", 1, "s", "a b") }, "[I]   hi k=1 s=\"a b\"\n"},
+		{"levels", func(l *slog.Logger) { l.Warn("w"); l.Error("e") }, "[W] ? w\n<E> ! e\n"},
+		{"filtered", func(l *slog.Logger) { l.Debug("hidden") }, ""},
+		{"inherited", func(l *slog.Logger) { l.With("req", 7).Info("hi", "k", 1) }, "[I]   hi req=7 k=1\n"},
+		{"group", func(l *slog.Logger) { l.WithGroup("http").Info("hi", "status", 200) }, "[I]   hi http.status=200\n"},
+		{"nested group", func(l *slog.Logger) {
+			l.With("a", 1).WithGroup("g").With("b", 2).Info("hi", slog.Group("sub", "c", 3))
+		}, "[I]   hi a=1 g.b=2 g.sub.c=3\n"},
+		{"empty group", func(l *slog.Logger) { l.WithGroup("").Info("hi", slog.Group("none")) }, "[I]   hi\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+			lg.SetPrintModule(false)
+			tt.log(slog.New(NewSlogHandler(lg)))
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		in   slog.Level
+		want LogLevel
+	}{
+		{slog.LevelDebug - 4, LevelTrace},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 4, LevelError},
+	}
+	for _, tt := range tests {
+		if got := slogLevel(tt.in); got != tt.want {
+			t.Errorf("slogLevel(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	lg, _ := newTestLogger(t, WithLevel(LevelWarn))
+	h := NewSlogHandler(lg)
+	ctx := context.Background()
+	if h.Enabled(ctx, slog.LevelInfo) || !h.Enabled(ctx, slog.LevelWarn) {
+		t.Fatal("Enabled doesn't follow the logger level")
+	}
+	lg.SetLevel(LevelDebug)
+	if !h.Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("Enabled doesn't follow a level change")
+	}
+}
+
+func TestSlogHandlerRecordTime(t *testing.T) {
+	lg, buf := newTestLogger(t, WithPrintTime(true), WithLevel(LevelInfo))
+	lg.SetPrintModule(false)
+	lg.SetClock(func() time.Time { return time.Date(2026, 10, 14, 9, 5, 7, 0, time.UTC) })
+	h := NewSlogHandler(lg)
+
+	r := slog.NewRecord(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, "recorded", 0)
+	h.Handle(context.Background(), r)
+	h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "no time", 0))
+
+	if got, want := buf.String(), "2026/01/02 03:04:05 [I]   recorded\n2026/10/14 09:05:07 [I]   no time\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
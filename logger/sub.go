@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weak"
+)
+
+// levels holds the minimum level of a logger and of the sub loggers that
+// follow it when propagation is enabled
+type levels struct {
+	maxLogLevel atomic.Int32
+	propagate   atomic.Bool
+
+	childMu  sync.Mutex
+	children []weak.Pointer[levels] // short-lived sub loggers can be collected
+}
+
+func newLevels(level LogLevel) *levels {
+	l := &levels{}
+	l.maxLogLevel.Store(int32(level))
+	return l
+}
+
+// SetLevel changes the minimum level, safe to call from any goroutine
+// including signal handlers. Filtering happens when a message is logged,
+// so messages already queued are written regardless of the new level.
+func (l *levels) SetLevel(level LogLevel) {
+	l.maxLogLevel.Store(int32(level))
+	l.propagateLevel(level)
+}
+
+// propagateLevel applies level to the sub loggers when propagation is on
+func (l *levels) propagateLevel(level LogLevel) {
+	if !l.propagate.Load() {
+		return
+	}
+
+	for _, c := range l.liveChildren() {
+		c.SetLevel(level)
+	}
+}
+
+// addChild tracks c for propagation
+func (l *levels) addChild(c *levels) {
+	l.childMu.Lock()
+	defer l.childMu.Unlock()
+	l.pruneChildren()
+	l.children = append(l.children, weak.Make(c))
+}
+
+// liveChildren returns the sub loggers that weren't collected yet
+func (l *levels) liveChildren() []*levels {
+	l.childMu.Lock()
+	defer l.childMu.Unlock()
+	l.pruneChildren()
+	children := make([]*levels, 0, len(l.children))
+	for _, w := range l.children {
+		if c := w.Value(); c != nil {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// pruneChildren drops collected sub loggers, must be called with childMu
+// held
+func (l *levels) pruneChildren() {
+	live := l.children[:0]
+	for _, w := range l.children {
+		if w.Value() != nil {
+			live = append(live, w)
+		}
+	}
+	clear(l.children[len(live):])
+	l.children = live
+}
+
+// GetLevel returns the current minimum level
+func (l *levels) GetLevel() LogLevel {
+	return LogLevel(l.maxLogLevel.Load())
+}
+
+// OverrideLevel sets the level and returns a function restoring the
+// previous one, calling it again does nothing. Nested overrides restored
+// in reverse order end at the original level. It affects every goroutine
+// using the logger, AtLevel doesn't.
+func (l *levels) OverrideLevel(level LogLevel) (restore func()) {
+	prev := LogLevel(l.maxLogLevel.Swap(int32(level)))
+	l.propagateLevel(level)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.SetLevel(prev) })
+	}
+}
+
+// SetPropagateLevel makes SetLevel also apply to the sub loggers
+func (l *levels) SetPropagateLevel(propagate bool) {
+	l.propagate.Store(propagate)
+}
+
+// Sub returns a child logger rendered as "PARENT/NAME". It starts at the
+// parent's level and shares its writers and consumer goroutine, so ordering
+// between parent and child is kept and closing the parent flushes it.
+func (lg *Logger) Sub(name string, color Color) *Logger {
+	child := lg.WithModule(lg.module+"/"+name, color)
+	child.propagate.Store(lg.propagate.Load())
+
+	lg.levels.addChild(child.levels)
+
+	return child
+}
+
+// WithModule returns a logger rendered as module in color, sharing the
+// writers and consumer goroutine of lg. It starts at the level of lg and
+// has its own level afterwards.
+func (lg *Logger) WithModule(module string, color Color) *Logger {
+	child := lg.Clone()
+	child.module = module
+	child.color = color
+	registerModule(module)
+	if l, ok := moduleLevel(module); ok {
+		child.levels.SetLevel(l)
+	}
+	return child
+}
+
+// Clone returns a copy of lg with its own level, sharing writers, options
+// and the consumer goroutine
+func (lg *Logger) Clone() *Logger {
+	child := lg.derive()
+	child.levels = newLevels(lg.GetLevel())
+	return child
+}
+
+// AtLevel returns a logger sharing everything with lg except the level,
+// which is set to level. Messages sent through other loggers are not
+// affected.
+func (lg *Logger) AtLevel(level LogLevel) *Logger {
+	child := lg.derive()
+	child.levels = newLevels(level)
+	return child
+}
+
+// derive returns a shallow copy of lg, closing it only flushes
+func (lg *Logger) derive() *Logger {
+	child := *lg
+	child.derived = true
+	return &child
+}
+
+// Close the logger (flushes remaining messages), messages logged
+// afterwards are written synchronously. It is safe to call concurrently
+// and from hooks, which don't wait for the remaining messages. Derived
+// loggers only flush, the writers stay open until the root is closed.
+func (lg *Logger) Close() {
+	lg.CloseContext(context.Background())
+}
+
+// CloseWithTimeout is Close giving up on the remaining messages after d
+func (lg *Logger) CloseWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return lg.CloseContext(ctx)
+}
+
+// CloseContext is Close giving up on the remaining messages when ctx is
+// done, the error tells how many queued messages were abandoned. Writers
+// are only closed once everything was written.
+func (lg *Logger) CloseContext(ctx context.Context) error {
+	if lg.derived {
+		lg.Flush()
+		return nil
+	}
+	return lg.close(ctx)
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSubOrdering(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("APP", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	loggers := []*Logger{lg, lg.Sub("HTTP", Blue), lg.Sub("DB", Green)}
+	loggers = append(loggers, loggers[1].Sub("TLS", Cyan))
+
+	var want strings.Builder
+	modules := []string{"APP", "APP/HTTP", "APP/DB", "APP/HTTP/TLS"}
+	for i := 0; i < 50; i++ {
+		n := i % len(loggers)
+		loggers[n].Infof("line %d", i)
+		fmt.Fprintf(&want, "[%s] [I]   line %d\n", modules[n], i)
+	}
+	lg.Close()
+
+	if buf.String() != want.String() {
+		t.Fatalf("output:\n%s\nwant:\n%s", buf, want.String())
+	}
+}
+
+func TestSubLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		propagate bool
+		want      LogLevel // of the child after the parent changes to Error
+	}{
+		{"independent", false, LevelWarn},
+		{"propagated", true, LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, _ := newTestLogger(t, WithLevel(LevelWarn))
+			lg.SetPropagateLevel(tt.propagate)
+			child := lg.Sub("C", Blue)
+			grandchild := child.Sub("G", Blue)
+			if child.GetLevel() != LevelWarn {
+				t.Fatalf("child starts at %v, want the parent's level", child.GetLevel())
+			}
+
+			lg.SetLevel(LevelError)
+			if got := child.GetLevel(); got != tt.want {
+				t.Fatalf("child level = %v, want %v", got, tt.want)
+			}
+			if got := grandchild.GetLevel(); got != tt.want {
+				t.Fatalf("grandchild level = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubCollected(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	lg.SetPropagateLevel(true)
+	kept := lg.Sub("KEPT", Blue)
+	for i := range 1000 {
+		lg.Sub(fmt.Sprint("REQ", i), Blue).Info("short-lived")
+	}
+	runtime.GC()
+
+	lg.Sub("LAST", Blue)
+	lg.levels.childMu.Lock()
+	n := len(lg.levels.children)
+	lg.levels.childMu.Unlock()
+	if n > 100 {
+		t.Fatalf("%d sub loggers tracked, the collected ones weren't dropped", n)
+	}
+	lg.SetLevel(LevelError)
+	if kept.GetLevel() != LevelError {
+		t.Fatalf("kept sub logger is at %v after propagation", kept.GetLevel())
+	}
+	runtime.KeepAlive(kept)
+}
+
+// closingBuffer is a managed writer recording whether it was closed
+type closingBuffer struct {
+	syncBuffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+func (b *closingBuffer) managed() {}
+
+func (b *closingBuffer) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func TestDerivedLoggers(t *testing.T) {
+	tests := []struct {
+		name   string
+		derive func(lg *Logger) *Logger
+		want   string
+	}{
+		{"clone", func(lg *Logger) *Logger { return lg.Clone() }, "[APP] [I]   task\n"},
+		{"with module", func(lg *Logger) *Logger { return lg.WithModule("MIGRATION-42", "") }, "[MIGRATION-42] [I]   task\n"},
+		{"with module colored", func(lg *Logger) *Logger { return lg.WithModule("M", Red) },
+			"\033[31m[M]\033[0m\033[90m \033[34m[I]   \033[0mtask\n"},
+		{"with module of a sub", func(lg *Logger) *Logger { return lg.Sub("HTTP", "").WithModule("JOB", "") }, "[JOB] [I]   task\n"},
+		{"at level", func(lg *Logger) *Logger { return lg.AtLevel(LevelWarn) }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			mode := ColorNever
+			if strings.Contains(tt.want, "\033") {
+				mode = ColorAlways
+			}
+			lg := NewLogger("APP", WithWriters(buf), WithColorMode(mode), WithPrintTime(false))
+			defer lg.Close()
+
+			tt.derive(lg).Info("task")
+			lg.Flush()
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDerivedLifecycle(t *testing.T) {
+	buf := &closingBuffer{}
+	lg := NewLogger("APP", WithWriters(buf), WithNoColor(), WithPrintTime(false))
+	task := lg.WithModule("TASK", "")
+	clone := lg.Clone()
+	clone.SetLevel(LevelError)
+
+	var want strings.Builder
+	for i := 0; i < 20; i++ {
+		l, module := lg, "APP"
+		if i%2 == 1 {
+			l, module = task, "TASK"
+		}
+		l.Infof("line %d", i)
+		clone.Infof("filtered %d", i)
+		fmt.Fprintf(&want, "[%s] [I]   line %d\n", module, i)
+	}
+
+	task.Close()
+	clone.Close()
+	if buf.isClosed() {
+		t.Fatal("closing a derived logger closed the writers")
+	}
+	if buf.String() != want.String() {
+		t.Fatalf("derived Close didn't flush:\n%s", buf)
+	}
+	if lg.GetLevel() != LevelPrint {
+		t.Fatalf("the clone's level leaked to the root: %v", lg.GetLevel())
+	}
+
+	lg.Info("root still open")
+	task.Info("task still open")
+	lg.Close()
+	if !buf.isClosed() {
+		t.Fatal("root Close didn't close the writers")
+	}
+	if !strings.HasSuffix(buf.String(), "[APP] [I]   root still open\n[TASK] [I]   task still open\n") {
+		t.Fatalf("output:\n%s", buf)
+	}
+}
@@ -0,0 +1,102 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+	"strings"
+	"sync"
+)
+
+// Severities used by SyslogWriter unless changed with SetSeverity
+var defaultSeverities = map[LogLevel]syslog.Priority{
+	LevelTrace: syslog.LOG_DEBUG,
+	LevelDebug: syslog.LOG_DEBUG,
+	LevelPrint: syslog.LOG_INFO,
+	LevelInfo:  syslog.LOG_INFO,
+	LevelWarn:  syslog.LOG_WARNING,
+	LevelError: syslog.LOG_ERR,
+	LevelFatal: syslog.LOG_CRIT,
+	LevelPanic: syslog.LOG_ALERT,
+}
+
+// SyslogWriter sends each line to syslog with the severity of its level.
+// Escape sequences are removed and the connection is reestablished after
+// the daemon restarts.
+type SyslogWriter struct {
+	w *syslog.Writer
+
+	mu         sync.Mutex
+	severities map[LogLevel]syslog.Priority
+}
+
+// NewSyslogWriter connects to the syslog daemon at addr, an empty network
+// uses the local daemon. Lines are tagged with tag and use the user
+// facility.
+func NewSyslogWriter(network, addr, tag string) (*SyslogWriter, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogWriter{w: w, severities: defaultSeverities}, nil
+}
+
+// SetSeverity changes the syslog severity messages at level are sent with
+func (w *SyslogWriter) SetSeverity(level LogLevel, p syslog.Priority) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	severities := make(map[LogLevel]syslog.Priority, len(w.severities)+1)
+	for l, s := range w.severities {
+		severities[l] = s
+	}
+	severities[level] = p & 0x07
+	w.severities = severities
+}
+
+// Write sends p at the severity of Info
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(LevelInfo, p)
+}
+
+// WriteLevel sends p with the severity mapped from level
+func (w *SyslogWriter) WriteLevel(level LogLevel, p []byte) (int, error) {
+	w.mu.Lock()
+	severity, ok := w.severities[level]
+	w.mu.Unlock()
+	if !ok {
+		severity = syslog.LOG_INFO
+	}
+
+	msg := strings.TrimSuffix(stripANSI(string(p)), "\n")
+	var err error
+	switch severity {
+	case syslog.LOG_EMERG:
+		err = w.w.Emerg(msg)
+	case syslog.LOG_ALERT:
+		err = w.w.Alert(msg)
+	case syslog.LOG_CRIT:
+		err = w.w.Crit(msg)
+	case syslog.LOG_ERR:
+		err = w.w.Err(msg)
+	case syslog.LOG_WARNING:
+		err = w.w.Warning(msg)
+	case syslog.LOG_NOTICE:
+		err = w.w.Notice(msg)
+	case syslog.LOG_DEBUG:
+		err = w.w.Debug(msg)
+	default:
+		err = w.w.Info(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the connection to the daemon
+func (w *SyslogWriter) Close() error {
+	return w.w.Close()
+}
+
+func (w *SyslogWriter) managed() {}
@@ -0,0 +1,118 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenSyslog listens for datagrams on the unix socket at path
+func listenSyslog(t *testing.T, path string) *net.UnixConn {
+	t.Helper()
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readSyslog returns the priority and the message of the next datagram
+func readSyslog(t *testing.T, conn *net.UnixConn) (string, string) {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt := string(buf[:n])
+	end := strings.IndexByte(pkt, '>')
+	if !strings.HasPrefix(pkt, "<") || end < 0 {
+		t.Fatalf("datagram %q has no priority", pkt)
+	}
+	_, msg, _ := strings.Cut(pkt, "]: ")
+	return pkt[1:end], strings.TrimSuffix(msg, "\n")
+}
+
+// syslogPath is a socket path short enough for sun_path
+func syslogPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "log.sock")
+}
+
+func TestSyslogWriter(t *testing.T) {
+	path := syslogPath(t)
+	conn := listenSyslog(t, path)
+	sw, err := NewSyslogWriter("unixgram", path, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sw.SetSeverity(LevelPrint, syslog.LOG_NOTICE)
+	lg := NewLogger("TEST", WithWriters(sw), WithSync(true), WithColorMode(ColorAlways), WithPrintTime(false))
+	defer lg.Close()
+
+	// user facility is 1, the priority is facility*8 + severity
+	tests := []struct {
+		name     string
+		log      func()
+		priority string
+		msg      string
+	}{
+		{"trace", func() { lg.SetLevel(LevelTrace); lg.Trace("t") }, "15", "[TEST] [T]   t"},
+		{"debug", func() { lg.Debug("d") }, "15", "[TEST] [D]   d"},
+		{"print", func() { lg.Print("p") }, "13", "[TEST] p"},
+		{"info", func() { lg.Info("i") }, "14", "[TEST] [I]   i"},
+		{"warn", func() { lg.Warn("w") }, "12", "[TEST] [W] ? w"},
+		{"error", func() { lg.Error("e") }, "11", "[TEST] <E> ! e"},
+		{"plain write", func() { sw.Write([]byte("raw\n")) }, "14", "raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.log()
+			priority, msg := readSyslog(t, conn)
+			if priority != tt.priority || msg != tt.msg {
+				t.Fatalf("got <%s> %q, want <%s> %q", priority, msg, tt.priority, tt.msg)
+			}
+		})
+	}
+}
+
+func TestSyslogWriterReconnect(t *testing.T) {
+	path := syslogPath(t)
+	conn := listenSyslog(t, path)
+	sw, err := NewSyslogWriter("unixgram", path, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sw.Close()
+
+	sw.WriteLevel(LevelWarn, []byte("before\n"))
+	if _, msg := readSyslog(t, conn); msg != "before" {
+		t.Fatalf("got %q", msg)
+	}
+
+	// the daemon restarts on the same socket
+	conn.Close()
+	os.Remove(path)
+	conn = listenSyslog(t, path)
+
+	if _, err := sw.WriteLevel(LevelError, []byte("after\n")); err != nil {
+		t.Fatalf("write after the restart: %v", err)
+	}
+	if priority, msg := readSyslog(t, conn); priority != "11" || msg != "after" {
+		t.Fatalf("got <%s> %q", priority, msg)
+	}
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"strings"
+)
+
+// TableOption configures Logger.Table
+type TableOption func(*tableConfig)
+
+type tableConfig struct {
+	maxWidth int
+}
+
+// TableMaxWidth truncates cells wider than n columns with an ellipsis
+func TableMaxWidth(n int) TableOption {
+	return func(c *tableConfig) { c.maxWidth = n }
+}
+
+// Table prints rows aligned in columns below a bold header, one Print
+// message per line so highlighting applies to the cells. Columns holding
+// only numbers are right aligned.
+func (lg *Logger) Table(headers []string, rows [][]string, opts ...TableOption) {
+	if !lg.enabled(LevelPrint) {
+		return
+	}
+	c := &tableConfig{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	cols := len(headers)
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	cell := func(r []string, i int) string {
+		if i >= len(r) {
+			return ""
+		}
+		if c.maxWidth > 0 && visibleWidth(r[i]) > c.maxWidth {
+			return truncate(stripANSI(r[i]), c.maxWidth)
+		}
+		return r[i]
+	}
+
+	widths := make([]int, cols)
+	numeric := make([]bool, cols)
+	for i := range cols {
+		widths[i] = visibleWidth(cell(headers, i))
+		numeric[i] = len(rows) > 0
+		for _, r := range rows {
+			s := cell(r, i)
+			widths[i] = max(widths[i], visibleWidth(s))
+			if v := stripANSI(s); v != "" && !isNumber(strings.TrimPrefix(v, "-")) {
+				numeric[i] = false
+			}
+		}
+	}
+
+	line := func(r []string) string {
+		var b strings.Builder
+		for i := range cols {
+			s := cell(r, i)
+			pad := strings.Repeat(" ", widths[i]-visibleWidth(s))
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			if numeric[i] {
+				b.WriteString(pad + s)
+			} else {
+				b.WriteString(s + pad)
+			}
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+
+	if len(headers) > 0 {
+		m := lg.message(LevelPrint, line(headers))
+		m.style = Bold
+		lg.emit(m)
+	}
+	for _, r := range rows {
+		lg.emit(lg.message(LevelPrint, line(r)))
+	}
+}
+
+// truncate shortens s to n columns ending in an ellipsis
+func truncate(s string, n int) string {
+	var b strings.Builder
+	w := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if w+rw > n-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	return b.String() + "…"
+}
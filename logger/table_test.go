@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	up := string(Green) + "up" + string(Reset)
+	tests := []struct {
+		name    string
+		headers []string
+		rows    [][]string
+		opts    []TableOption
+		want    []string
+	}{
+		{
+			name:    "three columns",
+			headers: []string{"NAME", "SIZE", "STATE"},
+			rows:    [][]string{{"api", "12", up}, {"worker", "3", "down"}},
+			want: []string{
+				"NAME    SIZE  STATE",
+				"api       12  " + up,
+				"worker     3  down",
+			},
+		},
+		{
+			name:    "max width",
+			headers: []string{"NAME", "STATE"},
+			rows:    [][]string{{"worker-long-name", "down"}, {string(Red) + "very-long-red" + string(Reset), "up"}},
+			opts:    []TableOption{TableMaxWidth(6)},
+			want: []string{
+				"NAME    STATE",
+				"worke…  down",
+				"very-…  up",
+			},
+		},
+		{
+			name:    "wide runes",
+			headers: []string{"CITY", "N"},
+			rows:    [][]string{{"東京", "1"}, {"Praha", "20"}},
+			want: []string{
+				"CITY    N",
+				"東京    1",
+				"Praha  20",
+			},
+		},
+		{
+			name:    "short rows",
+			headers: []string{"A", "B", "C"},
+			rows:    [][]string{{"x"}, {"y", "zz", "w"}},
+			want: []string{
+				"A  B   C",
+				"x",
+				"y  zz  w",
+			},
+		},
+		{
+			name: "no headers",
+			rows: [][]string{{"a", "1.5"}, {"bb", "-2"}},
+			want: []string{
+				"a   1.5",
+				"bb   -2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t)
+			lg.SetPrintModule(false)
+			lg.Table(tt.headers, tt.rows, tt.opts...)
+
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestTableColors(t *testing.T) {
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.SetPrintModule(false)
+	lg.Table([]string{"NAME", "SIZE"}, [][]string{{"api", "12"}})
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], string(Bold)+"NAME  SIZE") {
+		t.Errorf("header %q isn't bold", lines[0])
+	}
+	// cells are highlighted like any Print message
+	if !strings.Contains(lines[1], string(Cyan)+"12"+string(Reset)) {
+		t.Errorf("row %q isn't highlighted", lines[1])
+	}
+}
+
+func TestTableFiltered(t *testing.T) {
+	lg, buf := newTestLogger(t, WithLevel(LevelInfo))
+	lg.Table([]string{"A"}, [][]string{{"x"}})
+	if buf.String() != "" {
+		t.Fatalf("table below the level was printed: %q", buf)
+	}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+// LogTo logs v at level and also writes the line, without colors, to w
+// before returning so w may be closed right after. It returns the error
+// of writing to w.
+func (lg *Logger) LogTo(w io.Writer, level LogLevel, v ...any) error {
+	return lg.logToDepth(1, w, level, v...)
+}
+
+// InfoTo is LogTo at Info
+func (lg *Logger) InfoTo(w io.Writer, v ...any) error {
+	return lg.logToDepth(1, w, LevelInfo, v...)
+}
+
+func (lg *Logger) logToDepth(depth int, w io.Writer, level LogLevel, v ...any) error {
+	if !lg.enabled(level) {
+		if lg.ring.Load() != nil {
+			lg.record(lg.message(level, fmt.Sprint(v...)))
+		}
+		return nil
+	}
+	m := lg.message(level, fmt.Sprint(v...))
+	if lg.reportCaller.Load() {
+		m.caller = caller(depth + 1 + int(lg.callerSkip.Load()))
+	}
+
+	r := m
+	redact(&r)
+	b := getBuffer()
+	defer putBuffer(b)
+	switch lg.opts.Load().format {
+	case FormatJSON:
+		lg.writeJSON(b, r, r.time)
+	case FormatLogfmt:
+		lg.writeLogfmt(b, r, r.time)
+	default:
+		lg.render(b, r, r.time, false)
+	}
+	_, err := w.Write(b.Bytes())
+
+	lg.emit(m)
+	if err != nil {
+		return fmt.Errorf("couldn't write log line: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// errWriter fails every write
+type errWriter struct{ err error }
+
+func (w errWriter) Write(p []byte) (int, error) { return 0, w.err }
+
+func TestLogTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		log   func(lg *Logger, w io.Writer) error
+		main  string // substring of the normal output, empty for none
+		extra string
+	}{
+		{"plain", nil, func(lg *Logger, w io.Writer) error { return lg.InfoTo(w, "summary: ", 3, " jobs") },
+			"[I]   summary: 3 jobs\n", "[I]   summary: 3 jobs\n"},
+		{"colors stripped", []Option{WithColorMode(ColorAlways)}, func(lg *Logger, w io.Writer) error {
+			return lg.LogTo(w, LevelWarn, "disk full")
+		}, string(Yellow) + "[W] ? " + string(Reset), "[W] ? disk full\n"},
+		{"json", []Option{WithJSON()}, func(lg *Logger, w io.Writer) error { return lg.InfoTo(w, "done") },
+			`"msg":"done"`, `"msg":"done"`},
+		{"filtered", []Option{WithLevel(LevelWarn)}, func(lg *Logger, w io.Writer) error { return lg.InfoTo(w, "done") },
+			"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, tt.opts...)
+			lg.SetPrintModule(false)
+			extra := &syncBuffer{}
+			if err := tt.log(lg, extra); err != nil {
+				t.Fatal(err)
+			}
+			// extra was written before returning, without waiting for a Flush
+			if got := extra.String(); !strings.Contains(got, tt.extra) || tt.extra == "" && got != "" {
+				t.Errorf("extra writer got %q, want %q", got, tt.extra)
+			}
+			if strings.Contains(extra.String(), "\033[") {
+				t.Errorf("colors in the extra writer: %q", extra)
+			}
+			lg.Flush()
+			if got := buf.String(); !strings.Contains(got, tt.main) || tt.main == "" && got != "" {
+				t.Errorf("normal output %q, want %q", got, tt.main)
+			}
+		})
+	}
+}
+
+func TestLogToError(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	lg.SetPrintModule(false)
+	broken := errors.New("disk full")
+	err := lg.InfoTo(errWriter{broken}, "summary")
+	if !errors.Is(err, broken) {
+		t.Fatalf("err = %v, want it to wrap %v", err, broken)
+	}
+	if got := buf.String(); got != "[I]   summary\n" {
+		t.Fatalf("normal output %q, want the message anyway", got)
+	}
+}
+
+func TestLogToRedacts(t *testing.T) {
+	t.Cleanup(ClearRedactions)
+	AddRedaction(regexp.MustCompile(`tok_[a-z0-9]+`), "[REDACTED]")
+	lg, _ := newTestLogger(t)
+	extra := &syncBuffer{}
+	lg.InfoTo(extra, "using tok_s3cr3t")
+	if got := extra.String(); strings.Contains(got, "s3cr3t") || !strings.Contains(got, "[REDACTED]") {
+		t.Fatalf("extra writer got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import "sync/atomic"
+
+// LevelTag is how a level is marked in text output
+type LevelTag struct {
+	Text  string // rendered before the message, including any decoration
+	Color Color
+}
+
+// Theme controls the colors and decorations of text output
+type Theme struct {
+	Levels map[LogLevel]LevelTag
+
+	// Prefix colors the timestamp and the body of Print messages
+	Prefix Color
+	// ModuleColor overrides the colors of the loggers when set
+	ModuleColor Color
+	// ModuleBrackets renders "[HTTP]" instead of "HTTP"
+	ModuleBrackets bool
+}
+
+var (
+	// ThemeDefault is the classic look of the logger
+	ThemeDefault = &Theme{
+		Levels: map[LogLevel]LevelTag{
+			LevelTrace: {"[T]   ", Cyan},
+			LevelDebug: {"[D]   ", Grey},
+			LevelInfo:  {"[I]   ", Blue},
+			LevelWarn:  {"[W] ? ", Yellow},
+			LevelError: {"<E> ! ", Red},
+			LevelFatal: {"<F>!!! ", Red},
+			LevelPanic: {"<P>!!! ", Red},
+		},
+		Prefix:         Grey,
+		ModuleBrackets: true,
+	}
+
+	// ThemeMonochrome uses only bold and dim styles
+	ThemeMonochrome = &Theme{
+		Levels: map[LogLevel]LevelTag{
+			LevelTrace: {"[T]   ", Dim},
+			LevelDebug: {"[D]   ", Dim},
+			LevelInfo:  {"[I]   ", ""},
+			LevelWarn:  {"[W] ? ", Bold},
+			LevelError: {"<E> ! ", Bold},
+			LevelFatal: {"<F>!!! ", Combine(Bold, Underline)},
+			LevelPanic: {"<P>!!! ", Combine(Bold, Underline)},
+		},
+		Prefix:         Dim,
+		ModuleColor:    Bold,
+		ModuleBrackets: true,
+	}
+
+	// ThemeSolarizedDark uses the solarized accent colors
+	ThemeSolarizedDark = &Theme{
+		Levels: map[LogLevel]LevelTag{
+			LevelTrace: {"[T]   ", Color256(37)},
+			LevelDebug: {"[D]   ", Color256(240)},
+			LevelInfo:  {"[I]   ", Color256(33)},
+			LevelWarn:  {"[W] ? ", Color256(136)},
+			LevelError: {"<E> ! ", Color256(160)},
+			LevelFatal: {"<F>!!! ", Combine(Bold, Color256(125))},
+			LevelPanic: {"<P>!!! ", Combine(Bold, Color256(125))},
+		},
+		Prefix:         Color256(240),
+		ModuleBrackets: true,
+	}
+)
+
+var defaultTheme atomic.Pointer[Theme]
+
+// DefaultTheme returns the theme of newly created loggers
+func DefaultTheme() *Theme {
+	if t := defaultTheme.Load(); t != nil {
+		return t
+	}
+	return ThemeDefault
+}
+
+// SetDefaultTheme sets the theme of loggers created afterwards
+func SetDefaultTheme(t *Theme) {
+	defaultTheme.Store(t)
+}
+
+// SetTheme changes the theme of lg and the loggers sharing its writers,
+// messages already queued keep the previous theme. The theme must not be
+// modified afterwards.
+func (lg *Logger) SetTheme(t *Theme) {
+	if t == nil {
+		t = DefaultTheme()
+	}
+	lg.control(func() {
+		lg.updateOpts(func(o *renderOptions) { o.theme = t })
+	})
+}
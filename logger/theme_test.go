@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThemes(t *testing.T) {
+	levels := []LogLevel{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal, LevelPrint}
+	tests := []struct {
+		name  string
+		theme *Theme
+		want  []string // one line per level
+	}{
+		{"default", ThemeDefault, []string{
+			"\033[34m[TEST]\033[0m\033[90m \033[36m[T]   \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m \033[90m[D]   \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m \033[34m[I]   \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m \033[33m[W] ? \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m \033[31m<E> ! \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m \033[31m<F>!!! \033[0mhi",
+			"\033[34m[TEST]\033[0m\033[90m hi",
+		}},
+		{"monochrome", ThemeMonochrome, []string{
+			"\033[1m[TEST]\033[0m\033[2m \033[2m[T]   \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m \033[2m[D]   \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m \033[0m[I]   \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m \033[1m[W] ? \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m \033[1m<E> ! \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m \033[1m\033[4m<F>!!! \033[0mhi",
+			"\033[1m[TEST]\033[0m\033[2m hi",
+		}},
+		{"custom tags", &Theme{
+			Levels: map[LogLevel]LevelTag{
+				LevelInfo: {"INFO  ", Green},
+				LevelWarn: {"WARN  ", Yellow},
+			},
+			Prefix: Grey,
+		}, []string{
+			"\033[34mTEST\033[0m\033[90m hi",
+			"\033[34mTEST\033[0m\033[90m hi",
+			"\033[34mTEST\033[0m\033[90m \033[32mINFO  \033[0mhi",
+			"\033[34mTEST\033[0m\033[90m \033[33mWARN  \033[0mhi",
+			"\033[34mTEST\033[0m\033[90m hi",
+			"\033[34mTEST\033[0m\033[90m hi",
+			"\033[34mTEST\033[0m\033[90m hi",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithColor(Blue), WithColorMode(ColorAlways), WithLevel(LevelTrace))
+			lg.SetExitFunc(func(int) {})
+			lg.SetTheme(tt.theme)
+			for _, l := range levels {
+				lg.Log(l, "hi")
+			}
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				for j := range got {
+					t.Logf("%v: %q", levels[j], got[j])
+				}
+				t.Fatal("unexpected output")
+			}
+		})
+	}
+}
+
+func TestSetThemeOrdering(t *testing.T) {
+	buf := &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(buf), WithColorMode(ColorAlways), WithPrintTime(false))
+	lg.Info("before")
+	lg.SetTheme(ThemeMonochrome)
+	lg.Info("after")
+	lg.SetTheme(nil)
+	lg.Info("default")
+	lg.Close()
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], string(Blue)+"[I]") || !strings.Contains(lines[1], string(Reset)+"[I]") ||
+		!strings.Contains(lines[2], string(Blue)+"[I]") {
+		t.Fatalf("theme switch applied to the wrong messages:\n%q", buf.String())
+	}
+}
+
+func TestDefaultTheme(t *testing.T) {
+	t.Cleanup(func() { SetDefaultTheme(nil) })
+	SetDefaultTheme(ThemeSolarizedDark)
+	if DefaultTheme() != ThemeSolarizedDark {
+		t.Fatal("DefaultTheme doesn't return the set theme")
+	}
+	lg, buf := newTestLogger(t, WithColorMode(ColorAlways))
+	lg.Info("hi")
+	if !strings.Contains(buf.String(), string(Color256(33))+"[I]   ") {
+		t.Fatalf("new logger doesn't use the default theme: %q", buf)
+	}
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+)
+
+// Timer returns a function that logs how long it took until it was called,
+// meant for `defer lg.Timer("reindex")()`
+func (lg *Logger) Timer(msg string) func() {
+	return lg.timer(LevelInfo, msg, 0)
+}
+
+// TimerLevel is Timer logging at the given level
+func (lg *Logger) TimerLevel(level LogLevel, msg string) func() {
+	return lg.timer(level, msg, 0)
+}
+
+// TimerWarn is Timer logging at Warn instead of Info when the duration
+// exceeds threshold
+func (lg *Logger) TimerWarn(msg string, threshold time.Duration) func() {
+	return lg.timer(LevelInfo, msg, threshold)
+}
+
+func (lg *Logger) timer(level LogLevel, msg string, threshold time.Duration) func() {
+	start := lg.now()
+	return func() {
+		d := lg.now().Sub(start)
+		level := level
+		if threshold > 0 && d > threshold {
+			level = LevelWarn
+		}
+		lg.logDepth(1, level, msg+" took "+humanDuration(d))
+	}
+}
+
+// SetClock replaces the clock used for timestamps, rate limits and timers.
+// It is meant for tests and must be called before anything is logged.
+func (lg *Logger) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	lg.now = now
+}
+
+// humanDuration formats d with three significant digits in the largest
+// fitting unit, like "1.24s" or "850µs"
+func humanDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + humanDuration(-d)
+	}
+	if d < time.Minute {
+		d = roundSignificant(d)
+	}
+
+	var v float64
+	var unit string
+	switch {
+	case d < time.Microsecond:
+		return strconv.FormatInt(int64(d), 10) + "ns"
+	case d < time.Millisecond:
+		v, unit = float64(d)/float64(time.Microsecond), "µs"
+	case d < time.Second:
+		v, unit = float64(d)/float64(time.Millisecond), "ms"
+	case d < time.Minute:
+		v, unit = d.Seconds(), "s"
+	default:
+		return d.Round(time.Second).String()
+	}
+
+	prec := 2
+	switch {
+	case v >= 100:
+		prec = 0
+	case v >= 10:
+		prec = 1
+	}
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	return s + unit
+}
+
+// roundSignificant rounds d to three significant digits, so 999.9µs
+// becomes 1.00ms instead of 1000µs
+func roundSignificant(d time.Duration) time.Duration {
+	unit := time.Duration(1)
+	for d/unit >= 1000 {
+		unit *= 10
+	}
+	return d.Round(unit)
+}
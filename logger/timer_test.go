@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     func(lg *Logger) func()
+		durations []time.Duration // when the returned function is called
+		want      []string
+	}{
+		{
+			name:      "info",
+			start:     func(lg *Logger) func() { return lg.Timer("reindex") },
+			durations: []time.Duration{1500 * time.Millisecond},
+			want:      []string{"[I]   reindex took 1.50s"},
+		},
+		{
+			name:      "level",
+			start:     func(lg *Logger) func() { return lg.TimerLevel(LevelDebug, "query") },
+			durations: []time.Duration{850 * time.Microsecond},
+			want:      []string{"[D]   query took 850µs"},
+		},
+		{
+			name:      "below threshold",
+			start:     func(lg *Logger) func() { return lg.TimerWarn("sync", time.Second) },
+			durations: []time.Duration{200 * time.Millisecond},
+			want:      []string{"[I]   sync took 200ms"},
+		},
+		{
+			name:      "called again",
+			start:     func(lg *Logger) func() { return lg.TimerWarn("sync", time.Second) },
+			durations: []time.Duration{2 * time.Second, 10 * time.Millisecond},
+			want:      []string{"[W] ? sync took 2.00s", "[I]   sync took 10.0ms"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, buf := newTestLogger(t, WithLevel(LevelTrace))
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			now := start
+			lg.SetClock(func() time.Time { return now })
+			lg.SetPrintModule(false)
+
+			stop := tt.start(lg)
+			for _, d := range tt.durations {
+				now = start.Add(d)
+				stop()
+			}
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimerConcurrentStop(t *testing.T) {
+	lg, buf := newTestLogger(t)
+	stop := lg.TimerWarn("op", time.Nanosecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop()
+		}()
+	}
+	wg.Wait()
+	if n := strings.Count(buf.String(), "op took"); n != 8 {
+		t.Fatalf("logged %d times, want 8", n)
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0ns"},
+		{999, "999ns"},
+		{1500, "1.50µs"},
+		{12 * time.Millisecond, "12.0ms"},
+		{123 * time.Millisecond, "123ms"},
+		{1240 * time.Millisecond, "1.24s"},
+		{90 * time.Second, "1m30s"},
+		{-2 * time.Second, "-2.00s"},
+		{999999, "1.00ms"},
+		{59999 * time.Millisecond, "1m0s"},
+	}
+	for _, tt := range tests {
+		if got := humanDuration(tt.d); got != tt.want {
+			t.Errorf("humanDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
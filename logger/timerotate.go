@@ -0,0 +1,268 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// strftime directives understood in TimeRotatingFile patterns, as Go
+// layout and the digits they are rendered as
+var strftime = map[byte]struct{ layout, re string }{
+	'Y': {"2006", `\d{4}`},
+	'm': {"01", `\d{2}`},
+	'd': {"02", `\d{2}`},
+	'H': {"15", `\d{2}`},
+	'M': {"04", `\d{2}`},
+	'S': {"05", `\d{2}`},
+}
+
+// namePart is literal text or a Go layout of a file name pattern
+type namePart struct {
+	text   string
+	layout bool
+}
+
+// RotateOption configures TimeRotatingFile
+type RotateOption func(*timeRotatingFile)
+
+// RotateClock replaces time.Now as the clock deciding the period
+func RotateClock(now func() time.Time) RotateOption {
+	return func(r *timeRotatingFile) { r.now = now }
+}
+
+type timeRotatingFile struct {
+	dir    string     // taken literally, never formatted
+	parts  []namePart // of the base name
+	re     *regexp.Regexp
+	every  time.Duration
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	f         *os.File // nil after a failed open, retried on Write
+	closed    bool
+	name      string
+	period    time.Time // start of the period of the open file
+	lastCheck time.Time
+}
+
+// TimeRotatingFile returns a writer to a file named after the current
+// period, like "app-%Y-%m-%d.log" with rotateEvery of 24h. Only the file
+// name is a pattern, the directory is used as is. The name uses strftime
+// directives (%Y %m %d %H %M %S, %% for '%') when it contains a '%', the
+// other text is kept literally. Otherwise it is taken as a Go reference
+// time layout. On rotation files matching the pattern that are older than
+// maxAge are removed (0 keeps all). A file deleted from under the writer
+// is recreated.
+func TimeRotatingFile(pattern string, rotateEvery time.Duration, maxAge time.Duration, opts ...RotateOption) (io.WriteCloser, error) {
+	dir, base := filepath.Split(pattern)
+	r := &timeRotatingFile{
+		dir:    dir,
+		parts:  []namePart{{text: base, layout: true}},
+		every:  rotateEvery,
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+	if strings.Contains(base, "%") {
+		r.parts, r.re = parsePattern(base)
+	}
+	for _, o := range opts {
+		o(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.open(r.now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// periodStart returns the start of the period containing t, periods of up
+// to a day are aligned to local midnight
+func (r *timeRotatingFile) periodStart(t time.Time) time.Time {
+	if r.every <= 0 {
+		return time.Time{}
+	}
+	if r.every > 24*time.Hour {
+		return t.Truncate(r.every)
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight) / r.every * r.every)
+}
+
+// parsePattern splits a strftime pattern into literal text and layouts,
+// re matches the names it produces with a group per directive
+func parsePattern(pattern string) (parts []namePart, re *regexp.Regexp) {
+	var lit strings.Builder
+	expr := "^"
+	flush := func() {
+		if lit.Len() > 0 {
+			parts = append(parts, namePart{text: lit.String()})
+			expr += regexp.QuoteMeta(lit.String())
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i+1 == len(pattern) {
+			lit.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		d, ok := strftime[pattern[i]]
+		switch {
+		case pattern[i] == '%':
+			lit.WriteByte('%')
+		case !ok:
+			lit.WriteString(pattern[i-1 : i+1])
+		default:
+			flush()
+			parts = append(parts, namePart{text: d.layout, layout: true})
+			expr += "(" + d.re + ")"
+		}
+	}
+	flush()
+	return parts, regexp.MustCompile(expr + "$")
+}
+
+// format returns the file name for t
+func (r *timeRotatingFile) format(t time.Time) string {
+	var b strings.Builder
+	for _, p := range r.parts {
+		if p.layout {
+			b.WriteString(t.Format(p.text))
+		} else {
+			b.WriteString(p.text)
+		}
+	}
+	return b.String()
+}
+
+// parse returns the time a file name produced by format stands for
+func (r *timeRotatingFile) parse(name string, loc *time.Location) (time.Time, error) {
+	if r.re == nil {
+		return time.ParseInLocation(r.parts[0].text, name, loc)
+	}
+	m := r.re.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("'%s' doesn't match the pattern", name)
+	}
+	var layouts []string
+	for _, p := range r.parts {
+		if p.layout {
+			layouts = append(layouts, p.text)
+		}
+	}
+	return time.ParseInLocation(strings.Join(layouts, " "), strings.Join(m[1:], " "), loc)
+}
+
+// open opens the file for the period of now, must be called with mu held
+func (r *timeRotatingFile) open(now time.Time) error {
+	period := r.periodStart(now)
+	t := period
+	if r.every <= 0 {
+		t = now
+	}
+	name := r.dir + r.format(t)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	r.f, r.name, r.period, r.lastCheck = f, name, period, now
+	return nil
+}
+
+// reopen closes the current file and opens the one for now, must be called
+// with mu held. On failure no file is open and the next Write tries again.
+func (r *timeRotatingFile) reopen(now time.Time) error {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+	return r.open(now)
+}
+
+func (r *timeRotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	now := r.now()
+	switch {
+	case r.every > 0 && !r.periodStart(now).Equal(r.period):
+		if err := r.reopen(now); err != nil {
+			return 0, err
+		}
+		r.prune(now)
+	case r.f == nil:
+		// The last open failed, try again
+		if err := r.open(now); err != nil {
+			return 0, err
+		}
+	case now.Sub(r.lastCheck) >= time.Second:
+		// Recreate the file if it was removed externally
+		r.lastCheck = now
+		if _, err := os.Stat(r.name); os.IsNotExist(err) {
+			if err := r.reopen(now); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return r.f.Write(p)
+}
+
+func (r *timeRotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *timeRotatingFile) managed() {}
+
+// prune removes files in the directory matching the pattern whose period
+// is older than maxAge
+func (r *timeRotatingFile) prune(now time.Time) {
+	if r.maxAge <= 0 {
+		return
+	}
+
+	dir := r.dir
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		t, err := r.parse(e.Name(), now.Location())
+		if err != nil || e.Name() == filepath.Base(r.name) {
+			continue
+		}
+		if now.Sub(t) > r.maxAge {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock is a clock the test moves forward
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestTimeRotatingFileName(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 5, 7, 0, time.Local)
+	tests := []struct {
+		name    string
+		dir     string
+		pattern string
+		every   time.Duration
+		want    string
+	}{
+		{"daily", "logs", "app-%Y-%m-%d.log", 24 * time.Hour, "logs/app-2026-10-14.log"},
+		{"hourly", "logs", "app-%Y%m%d-%H.log", time.Hour, "logs/app-20261014-09.log"},
+		{"digits in dir", "s3-backup/01", "app-%Y-%m-%d.log", 24 * time.Hour, "s3-backup/01/app-2026-10-14.log"},
+		{"literal digits", "logs", "v2-app1-%d.log", 24 * time.Hour, "logs/v2-app1-14.log"},
+		{"percent", "logs", "100%%-%H%M%S.log", 0, "logs/100%-090507.log"},
+		{"unknown directive", "logs", "app-%q.log", 24 * time.Hour, "logs/app-%q.log"},
+		{"go layout", "logs", "app-2006-01-02.log", 24 * time.Hour, "logs/app-2026-10-14.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			w, err := TimeRotatingFile(filepath.Join(root, tt.dir, tt.pattern), tt.every, 0,
+				RotateClock(func() time.Time { return now }))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			want := filepath.Join(root, filepath.FromSlash(tt.want))
+			if got := w.(*timeRotatingFile).name; got != want {
+				t.Fatalf("name = %q, want %q", got, want)
+			}
+			if _, err := os.Stat(want); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTimeRotatingFileRollover(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2026, 10, 12, 23, 59, 0, 0, time.Local)}
+	w, err := TimeRotatingFile(filepath.Join(dir, "app-%Y-%m-%d.log"), 24*time.Hour, 36*time.Hour, RotateClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// keep a file that isn't part of the pattern
+	os.WriteFile(filepath.Join(dir, "other-2026-01-01.log"), nil, 0o644)
+
+	steps := []struct {
+		advance time.Duration
+		line    string
+	}{
+		{0, "first\n"},
+		{30 * time.Second, "same day\n"},
+		{time.Minute, "next day\n"},
+		{24 * time.Hour, "third day\n"},
+	}
+	for _, s := range steps {
+		clock.Add(s.advance)
+		if _, err := w.Write([]byte(s.line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{
+		"app-2026-10-13.log":   "next day\n",
+		"app-2026-10-14.log":   "third day\n",
+		"other-2026-01-01.log": "",
+	}
+	names := dirNames(t, dir)
+	if len(names) != len(want) {
+		t.Fatalf("files = %v, want the first day pruned", names)
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+	}
+}
+
+func TestTimeRotatingFileReopen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	clock := &testClock{now: time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local)}
+	w, err := TimeRotatingFile(filepath.Join(dir, "app-%Y-%m-%d.log"), 24*time.Hour, 0, RotateClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a file in place of the directory makes opening the next day's file fail
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(24 * time.Hour)
+	for range 2 {
+		if _, err := w.Write([]byte("lost\n")); err == nil || errors.Is(err, os.ErrClosed) {
+			t.Fatalf("write with a failing open = %v", err)
+		}
+	}
+
+	os.Remove(dir)
+	if _, err := w.Write([]byte("recovered\n")); err != nil {
+		t.Fatalf("write after the directory came back = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "app-2026-10-13.log"))
+	if err != nil || string(data) != "recovered\n" {
+		t.Fatalf("file = %q, %v", data, err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("write after Close = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	r := &timeRotatingFile{}
+	r.parts, r.re = parsePattern("s3-%Y.%m.%d-%H.log")
+	want := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	name := r.format(want)
+	if name != "s3-2026.10.14-09.log" {
+		t.Fatalf("format = %q", name)
+	}
+	got, err := r.parse(name, time.UTC)
+	if err != nil || !got.Equal(want) {
+		t.Fatalf("parse(%q) = %v, %v, want %v", name, got, err, want)
+	}
+	for _, bad := range []string{"s3-2026.10.14-09.log.gz", "s4-2026.10.14-09.log", strings.ToUpper(name)} {
+		if _, err := r.parse(bad, time.UTC); err == nil {
+			t.Errorf("parse(%q) matched", bad)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Messages longer than this are truncated unless changed with
+// SetMaxMessageSize
+const defaultMaxMessageSize = 64 << 10
+
+// SetMaxMessageSize truncates messages longer than n bytes before they are
+// queued, 0 disables the limit
+func (lg *Logger) SetMaxMessageSize(n int) {
+	lg.maxMessageSize.Store(int64(max(0, n)))
+}
+
+// truncate cuts msg at the limit on a rune boundary outside of escape
+// sequences and appends how much was removed
+func (lg *core) truncate(msg string) string {
+	limit := int(lg.maxMessageSize.Load())
+	if limit <= 0 || len(msg) <= limit {
+		return msg
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	// Don't leave half an escape sequence at the end
+	if esc := strings.LastIndexByte(msg[:cut], 0x1b); esc >= 0 {
+		if loc := ansiRe.FindStringIndex(msg[esc:]); loc == nil || loc[0] != 0 || esc+loc[1] > cut {
+			cut = esc
+		}
+	}
+	return msg[:cut] + "… [truncated " + Bytes(int64(len(msg)-cut)) + "]"
+}
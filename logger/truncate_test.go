@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		msg   string
+		want  string
+	}{
+		{"below limit", 10, "short", "short"},
+		{"at limit", 5, "exact", "exact"},
+		{"disabled", 0, strings.Repeat("x", 100), strings.Repeat("x", 100)},
+		{"cut", 4, "abcdefgh", "abcd… [truncated 4 B]"},
+		{"rune boundary", 4, "abcé!", "abc… [truncated 3 B]"},
+		{"escape kept whole", 8, "ab" + string(Red) + "cdef", "ab" + string(Red) + "c… [truncated 3 B]"},
+		{"escape not cut", 5, "ab" + string(Red) + "cdef", "ab… [truncated 9 B]"},
+		{"kibibytes", 1, strings.Repeat("y", 2049), "y… [truncated 2.0 KiB]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, _ := newTestLogger(t)
+			lg.SetMaxMessageSize(tt.limit)
+			if got := lg.truncate(tt.msg); got != tt.want {
+				t.Fatalf("truncate(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	lg, buf := newTestLogger(t, WithSync(false))
+	lg.SetPrintModule(false)
+	body := "HTTP/1.1 200 OK " + strings.Repeat("z", 5<<20)
+	lg.Info(body)
+	lg.Infof("%s", body)
+	lg.Info(func() string { return body })
+	lg.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("logged %d lines, want 3", len(lines))
+	}
+	for _, line := range lines {
+		if len(line) > defaultMaxMessageSize+64 {
+			t.Fatalf("line of %d bytes isn't bounded by the default limit", len(line))
+		}
+		if !strings.HasPrefix(line, "[I]   HTTP/1.1 200 OK zzz") || !strings.HasSuffix(line, "… [truncated 4.9 MiB]") {
+			t.Fatalf("line %.40q...%q", line, line[len(line)-30:])
+		}
+	}
+}
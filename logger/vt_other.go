@@ -0,0 +1,10 @@
+//go:build !windows
+
+package logger
+
+import "io"
+
+// enableVT is a no-op outside Windows, terminals understand escapes
+func enableVT(w io.Writer) bool {
+	return true
+}
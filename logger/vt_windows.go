@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+	vtMu      sync.Mutex
+	vtConsole = map[syscall.Handle]bool{}
+
+	// consoleVT is setConsoleVT, replaceable in tests
+	consoleVT = setConsoleVT
+)
+
+// enableVT turns on escape sequence processing for a console writer, it
+// reports false when the console can't interpret them so colors are
+// stripped instead. The result is cached per handle.
+func enableVT(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return true
+	}
+	h := syscall.Handle(f.Fd())
+
+	vtMu.Lock()
+	defer vtMu.Unlock()
+	if ok, done := vtConsole[h]; done {
+		return ok
+	}
+	ok = consoleVT(h)
+	vtConsole[h] = ok
+	return ok
+}
+
+// setConsoleVT enables virtual terminal processing on h, handles that
+// aren't consoles, like pipes to mintty, are left alone
+func setConsoleVT(h syscall.Handle) bool {
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return true
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := setConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
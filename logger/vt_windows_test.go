@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestEnableVT(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"enabled", true},
+		{"enable failed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Create(filepath.Join(t.TempDir(), "console"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			calls := 0
+			prev := consoleVT
+			consoleVT = func(syscall.Handle) bool {
+				calls++
+				return tt.enabled
+			}
+			defer func() { consoleVT = prev }()
+
+			for i := 0; i < 2; i++ {
+				if got := enableVT(f); got != tt.enabled {
+					t.Fatalf("enableVT = %v, want %v", got, tt.enabled)
+				}
+			}
+			if calls != 1 {
+				t.Fatalf("console mode set %d times, want once per handle", calls)
+			}
+			if !tt.enabled && useColor(ColorAuto, []io.Writer{f}) {
+				t.Fatal("colors enabled for a console without escape processing")
+			}
+		})
+	}
+}
+
+func TestEnableVTOtherWriters(t *testing.T) {
+	prev := consoleVT
+	consoleVT = func(syscall.Handle) bool {
+		t.Fatal("console mode set for a writer that isn't a file")
+		return false
+	}
+	defer func() { consoleVT = prev }()
+
+	if !enableVT(io.Discard) {
+		t.Fatal("enableVT(io.Discard) = false")
+	}
+}
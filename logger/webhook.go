@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the webhook text, Discord rejects content over 2000 characters
+const (
+	defaultWebhookLength = 2000
+	defaultWebhookQueue  = 1000
+)
+
+// WebhookOption configures NewWebhookHook
+type WebhookOption func(*WebhookHook)
+
+// WebhookRateLimit allows at most n posts per minute, the messages beyond
+// that are counted and reported in the next allowed post. The default is 10.
+func WebhookRateLimit(n int) WebhookOption {
+	return func(h *WebhookHook) { h.limit = n }
+}
+
+// WebhookClient sets the HTTP client, the default times out after 10s
+func WebhookClient(c *http.Client) WebhookOption {
+	return func(h *WebhookHook) { h.client = c }
+}
+
+// WebhookMaxLength splits posts so their text stays within n characters,
+// 2000 by default to fit Discord. Longer messages are cut.
+func WebhookMaxLength(n int) WebhookOption {
+	return func(h *WebhookHook) { h.maxLength = n }
+}
+
+// WebhookQueueSize keeps at most n messages waiting for delivery, the
+// oldest are dropped and counted beyond that. The default is 1000.
+func WebhookQueueSize(n int) WebhookOption {
+	return func(h *WebhookHook) { h.maxQueue = n }
+}
+
+// WebhookErrorLog logs delivery failures to lg at Debug
+func WebhookErrorLog(lg *Logger) WebhookOption {
+	return func(h *WebhookHook) { h.errLog = lg }
+}
+
+// WebhookHook posts messages at or above a level to a Slack or Discord
+// compatible webhook. Posts are made by a worker goroutine, messages
+// arriving while a post is in flight are batched into the next one and
+// split into several posts when they don't fit into one.
+type WebhookHook struct {
+	url       string
+	minLevel  LogLevel
+	limit     int
+	maxLength int
+	maxQueue  int
+	client    *http.Client
+	errLog    *Logger
+	hostname  string
+
+	mu         sync.Mutex
+	pending    []webhookEntry
+	dropped    int // messages removed from a full queue
+	suppressed int
+	window     time.Time // start of the current rate limit minute
+	posts      int
+	summary    *time.Timer // reports suppressed messages when the window ends
+	closed     bool
+
+	wake chan struct{}
+	quit chan struct{}
+	done chan struct{}
+}
+
+type webhookEntry struct {
+	Module    string `json:"module"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+	Hostname  string `json:"hostname"`
+}
+
+// NewWebhookHook starts the worker, register the hook with
+// lg.AddHook(h.Fire) and Close it before exiting to deliver what is left
+func NewWebhookHook(url string, minLevel LogLevel, opts ...WebhookOption) *WebhookHook {
+	h := &WebhookHook{
+		url:       url,
+		minLevel:  minLevel,
+		limit:     10,
+		maxLength: defaultWebhookLength,
+		maxQueue:  defaultWebhookQueue,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		wake:      make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	h.hostname, _ = os.Hostname()
+	for _, o := range opts {
+		o(h)
+	}
+	go h.run()
+	return h
+}
+
+// Fire is the Hook queueing the message for the worker
+func (h *WebhookHook) Fire(level LogLevel, module string, msg string, fields map[string]any) {
+	if level < h.minLevel {
+		return
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	if h.maxQueue > 0 && len(h.pending) >= h.maxQueue {
+		n := len(h.pending) - h.maxQueue + 1
+		h.pending = append(h.pending[:0], h.pending[n:]...)
+		h.dropped += n
+	}
+	h.pending = append(h.pending, webhookEntry{
+		Module:    module,
+		Level:     level.String(),
+		Message:   msg,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Hostname:  h.hostname,
+	})
+	h.mu.Unlock()
+	h.signal()
+}
+
+func (h *WebhookHook) signal() {
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close delivers the queued messages and stops the worker
+func (h *WebhookHook) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	h.mu.Unlock()
+
+	close(h.quit)
+	<-h.done
+	return nil
+}
+
+func (h *WebhookHook) run() {
+	defer close(h.done)
+	for {
+		select {
+		case <-h.wake:
+			h.deliver()
+		case <-h.quit:
+			h.deliver()
+			return
+		}
+	}
+}
+
+// deliver posts the pending messages in as many posts as needed unless
+// the rate limit is reached, in which case the rest is only counted
+func (h *WebhookHook) deliver() {
+	h.mu.Lock()
+	entries := h.pending
+	h.pending = nil
+	h.mu.Unlock()
+
+	for {
+		h.mu.Lock()
+		now := time.Now()
+		if now.Sub(h.window) >= time.Minute {
+			h.window, h.posts = now, 0
+		}
+		if h.posts >= h.limit {
+			h.suppressed += len(entries)
+			if h.summary == nil {
+				h.summary = time.AfterFunc(h.window.Add(time.Minute).Sub(now), h.signal)
+			}
+			h.mu.Unlock()
+			return
+		}
+		if len(entries) == 0 && h.suppressed == 0 && h.dropped == 0 {
+			h.mu.Unlock()
+			return
+		}
+		h.summary = nil
+		h.posts++
+		suppressed, dropped := h.suppressed, h.dropped
+		h.suppressed, h.dropped = 0, 0
+		h.mu.Unlock()
+
+		text, n := h.text(entries, suppressed, dropped)
+		if err := h.post(text, entries[:n], suppressed); err != nil && h.errLog != nil {
+			h.errLog.Debugf("couldn't deliver %d messages to webhook: %v", n, err)
+		}
+		entries = entries[n:]
+		if len(entries) == 0 {
+			return
+		}
+	}
+}
+
+// text renders the notes about lost messages and as many entries as fit
+// into maxLength, n is the number of entries used. There is at least one,
+// cut to fit when it has to.
+func (h *WebhookHook) text(entries []webhookEntry, suppressed, dropped int) (text string, n int) {
+	var lines []string
+	if dropped > 0 {
+		lines = append(lines, fmt.Sprintf("(%d messages dropped, the webhook queue was full)", dropped))
+	}
+	if suppressed > 0 {
+		lines = append(lines, fmt.Sprintf("(%d more messages suppressed by rate limit)", suppressed))
+	}
+	length := utf8.RuneCountInString(strings.Join(lines, "\n"))
+	for _, e := range entries {
+		line := fmt.Sprintf("[%s] %s %s: %s", e.Hostname, e.Level, e.Module, e.Message)
+		if len(lines) > 0 {
+			length++ // newline
+		}
+		if h.maxLength > 0 {
+			if n > 0 && length+utf8.RuneCountInString(line) > h.maxLength {
+				break
+			}
+			line = cutRunes(line, h.maxLength-length)
+		}
+		length += utf8.RuneCountInString(line)
+		lines = append(lines, line)
+		n++
+	}
+	return strings.Join(lines, "\n"), n
+}
+
+// cutRunes shortens s to at most n runes, marking the cut with "…"
+func cutRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+func (h *WebhookHook) post(text string, entries []webhookEntry, suppressed int) error {
+	// text is read by Slack, content by Discord
+	body, err := json.Marshal(map[string]any{
+		"text":       text,
+		"content":    text,
+		"entries":    entries,
+		"suppressed": suppressed,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
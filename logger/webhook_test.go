@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// webhookServer records the text of the posts it receives, block delays
+// the responses until it is closed
+type webhookServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	posts []string
+	got   chan struct{}
+	block chan struct{}
+}
+
+func newWebhookServer(t *testing.T, block bool) *webhookServer {
+	t.Helper()
+
+	s := &webhookServer{got: make(chan struct{}, 100)}
+	if block {
+		s.block = make(chan struct{})
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Content string `json:"content"`
+			Text    string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Content != body.Text {
+			t.Errorf("bad body: %v", err)
+		}
+		s.mu.Lock()
+		s.posts = append(s.posts, body.Content)
+		s.mu.Unlock()
+		s.got <- struct{}{}
+		if s.block != nil {
+			<-s.block
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) Posts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.posts...)
+}
+
+func TestWebhookSplitsPosts(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLength int
+		messages  []string
+		wantPosts int
+	}{
+		{"one post", 2000, []string{"a", "b", "c"}, 1},
+		{"split", 2000, repeat(strings.Repeat("x", 300), 20), 4},
+		{"long message", 100, []string{strings.Repeat("é", 400)}, 1},
+		{"unlimited", 0, repeat(strings.Repeat("x", 300), 20), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newWebhookServer(t, false)
+			h := NewWebhookHook(srv.URL, LevelError, WebhookMaxLength(tt.maxLength), WebhookRateLimit(100))
+			h.mu.Lock() // queue everything before the worker runs
+			for _, msg := range tt.messages {
+				h.pending = append(h.pending, webhookEntry{Module: "M", Level: "error", Message: msg, Hostname: "h"})
+			}
+			h.mu.Unlock()
+			h.Close()
+
+			posts := srv.Posts()
+			if len(posts) != tt.wantPosts {
+				t.Fatalf("%d posts, want %d", len(posts), tt.wantPosts)
+			}
+			lines := 0
+			for _, p := range posts {
+				if n := utf8.RuneCountInString(p); tt.maxLength > 0 && n > tt.maxLength {
+					t.Errorf("post of %d characters, limit %d", n, tt.maxLength)
+				}
+				lines += strings.Count(p, "\n") + 1
+			}
+			if lines != len(tt.messages) {
+				t.Errorf("%d lines delivered, want %d", lines, len(tt.messages))
+			}
+		})
+	}
+}
+
+func TestWebhookQueueLimit(t *testing.T) {
+	srv := newWebhookServer(t, true)
+	h := NewWebhookHook(srv.URL, LevelError, WebhookQueueSize(5))
+
+	h.Fire(LevelError, "M", "first", nil)
+	<-srv.got // the worker is busy with the first post
+	for i := 0; i < 20; i++ {
+		h.Fire(LevelError, "M", "queued", nil)
+	}
+	h.Fire(LevelInfo, "M", "below the level", nil)
+	h.mu.Lock()
+	queued := len(h.pending)
+	h.mu.Unlock()
+	if queued != 5 {
+		t.Fatalf("%d messages queued, want 5", queued)
+	}
+
+	close(srv.block)
+	h.Close()
+	posts := srv.Posts()
+	if len(posts) != 2 {
+		t.Fatalf("posts = %q, want 2", posts)
+	}
+	if !strings.HasPrefix(posts[1], "(15 messages dropped") || strings.Count(posts[1], "queued") != 5 {
+		t.Fatalf("second post = %q, want the drop count and 5 messages", posts[1])
+	}
+}
+
+func repeat(s string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = s
+	}
+	return out
+}
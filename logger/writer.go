@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// policyWriter attaches a color policy to a writer passed to New
+type policyWriter struct {
+	io.Writer
+	mode ColorMode
+}
+
+// Plain wraps w so it always receives output without ANSI escapes
+func Plain(w io.Writer) io.Writer {
+	return &policyWriter{Writer: w, mode: ColorNever}
+}
+
+// Colored wraps w so it always receives colored output
+func Colored(w io.Writer) io.Writer {
+	return &policyWriter{Writer: w, mode: ColorAlways}
+}
+
+// formatWriter fixes the output format of a writer passed to New
+type formatWriter struct {
+	io.Writer
+	format Format
+}
+
+// TextTo wraps w so it receives text output whatever the logger's format
+func TextTo(w io.Writer) io.Writer {
+	return &formatWriter{Writer: w, format: FormatText}
+}
+
+// JSONTo wraps w so it receives JSON lines whatever the logger's format
+func JSONTo(w io.Writer) io.Writer {
+	return &formatWriter{Writer: w, format: FormatJSON}
+}
+
+// LogfmtTo wraps w so it receives logfmt lines whatever the logger's
+// format
+func LogfmtTo(w io.Writer) io.Writer {
+	return &formatWriter{Writer: w, format: FormatLogfmt}
+}
+
+// levelWriter limits a writer passed to New to a range of levels
+type levelWriter struct {
+	io.Writer
+	min, max LogLevel
+}
+
+// Highest possible level, the upper bound of open level ranges
+const levelMax LogLevel = math.MaxInt32
+
+// Levels wraps w so it only receives messages with min <= level <= max
+func Levels(w io.Writer, min, max LogLevel) io.Writer {
+	return &levelWriter{Writer: w, min: min, max: max}
+}
+
+// MinLevel wraps w so it only receives messages at min and above. The
+// logger's own level still applies first.
+func MinLevel(w io.Writer, min LogLevel) io.Writer {
+	return Levels(w, min, levelMax)
+}
+
+// SplitStdStreams returns writers for New sending warnings and above to
+// stderr and everything else to stdout
+func SplitStdStreams() []io.Writer {
+	return []io.Writer{
+		Levels(os.Stdout, LevelTrace, LevelWarn-1),
+		Levels(os.Stderr, LevelWarn, levelMax),
+	}
+}
+
+// LevelWriter is a writer that wants the level of each line, like syslog.
+// The logger calls WriteLevel instead of Write for it.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level LogLevel, p []byte) (int, error)
+}
+
+// write hands p to the sink's writer, passing the level when it takes one
+func (s *sink) write(level LogLevel, p []byte) {
+	if s.buf != nil {
+		s.buf.Write(p) // flushes through the lock, see bufferSinks
+		return
+	}
+	if s.lock != nil {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+	if lw, ok := s.w.(LevelWriter); ok {
+		lw.WriteLevel(level, p)
+		return
+	}
+	s.w.Write(p)
+}
+
+// managedWriter is a writer made by this package, closed with the Logger
+type managedWriter interface {
+	io.Closer
+	managed()
+}
+
+// closeWriters closes the managed writers
+func (lg *core) closeWriters() {
+	lg.wmu.Lock()
+	defer lg.wmu.Unlock()
+	lg.flushBatch()
+	for _, w := range lg.writers {
+		if mw, ok := w.(managedWriter); ok {
+			mw.Close()
+		}
+	}
+}
+
+// WriterHandle identifies a writer added with AddWriter or SetOutput
+type WriterHandle int
+
+// AddWriter adds a destination, it receives messages logged after the
+// call but not those still queued
+func (lg *Logger) AddWriter(w io.Writer) WriterHandle {
+	s := newSink(w)
+	lg.control(func() {
+		lg.nextWriter++
+		s.handle = lg.nextWriter
+		s.resolveColor(lg.colorMode)
+		lg.setSinks(append(lg.sinks[:len(lg.sinks):len(lg.sinks)], s))
+	})
+	return s.handle
+}
+
+// RemoveWriter removes a destination once the messages queued before the
+// call have been written to it
+func (lg *Logger) RemoveWriter(h WriterHandle) {
+	lg.control(func() {
+		sinks := make([]sink, 0, len(lg.sinks))
+		for _, s := range lg.sinks {
+			if s.handle != h {
+				sinks = append(sinks, s)
+			}
+		}
+		lg.setSinks(sinks)
+	})
+}
+
+// SetOutput replaces every destination with w after the messages queued
+// before the call have been written
+func (lg *Logger) SetOutput(w io.Writer) WriterHandle {
+	s := newSink(w)
+	lg.control(func() {
+		lg.nextWriter++
+		s.handle = lg.nextWriter
+		s.resolveColor(lg.colorMode)
+		lg.setSinks([]sink{s})
+	})
+	return s.handle
+}
+
+// control runs fn on the consumer in order with queued messages, holding
+// the write lock, and waits for it
+func (lg *core) control(fn func()) {
+	lg.dispatch(logMessage{flush: true, ctrl: func() {
+		lg.wmu.Lock()
+		defer lg.wmu.Unlock()
+		fn()
+	}}, true)
+}
+
+// setSinks replaces the sinks and the raw writer list, must be called with
+// wmu held
+func (lg *core) setSinks(sinks []sink) {
+	lg.flushBatch()
+	writers := make([]io.Writer, len(sinks))
+	for i, s := range sinks {
+		writers[i] = s.w
+	}
+	lg.sinks, lg.writers = sinks, writers
+	lg.bufferSinks()
+}
+
+// sink is a destination of rendered lines
+type sink struct {
+	handle   WriterHandle
+	w        io.Writer
+	policy   ColorMode // ColorAuto defers to the logger's color mode
+	color    bool
+	tty      bool         // progress lines are redrawn in place
+	buf      *batchWriter // collects lines when batching
+	lock     *sync.Mutex  // shared with other loggers, see Serialize
+	min, max LogLevel
+
+	format    Format
+	ownFormat bool // format overrides the logger's, see TextTo
+}
+
+// formatOr returns the format of the sink, def when it has none of its own
+func (s *sink) formatOr(def Format) Format {
+	if s.ownFormat {
+		return s.format
+	}
+	return def
+}
+
+// accepts reports whether the sink takes messages at level
+func (s *sink) accepts(level LogLevel) bool {
+	return level >= s.min && level <= s.max
+}
+
+// newSinks unwraps policy writers, returning the sinks and the raw writers
+func newSinks(writers []io.Writer) ([]sink, []io.Writer) {
+	sinks := make([]sink, 0, len(writers))
+	raw := make([]io.Writer, 0, len(writers))
+	for _, w := range writers {
+		s := newSink(w)
+		sinks = append(sinks, s)
+		raw = append(raw, s.w)
+	}
+	return sinks, raw
+}
+
+// newSink unwraps nested wrappers, the outermost one wins
+func newSink(w io.Writer) sink {
+	s := sink{min: math.MinInt32, max: levelMax}
+	hasPolicy, hasLevels := false, false
+	for {
+		switch t := w.(type) {
+		case *serialWriter:
+			if s.lock == nil {
+				s.lock = t.mu
+			}
+			w = t.Writer
+			continue
+		case *formatWriter:
+			if !s.ownFormat {
+				s.format, s.ownFormat = t.format, true
+			}
+			w = t.Writer
+			continue
+		case *policyWriter:
+			if !hasPolicy {
+				s.policy, hasPolicy = t.mode, true
+			}
+			w = t.Writer
+			continue
+		case *levelWriter:
+			if !hasLevels {
+				s.min, s.max, hasLevels = t.min, t.max, true
+			}
+			w = t.Writer
+			continue
+		}
+		s.w = w
+		s.tty = isTerminal(w)
+		if s.lock == nil {
+			s.lock = stdLock(w)
+		}
+		return s
+	}
+}
+
+// resolveColors decides per sink whether colors are written
+func (lg *core) resolveColors() {
+	lg.wmu.Lock()
+	defer lg.wmu.Unlock()
+
+	lg.colorOutput = useColor(lg.colorMode, lg.writers)
+	for i := range lg.sinks {
+		lg.sinks[i].resolveColor(lg.colorMode)
+	}
+}
+
+// resolveColor applies the sink's policy or else the logger's mode
+func (s *sink) resolveColor(mode ColorMode) {
+	switch s.policy {
+	case ColorAuto:
+		s.color = useColor(mode, []io.Writer{s.w})
+	default:
+		s.color = s.policy == ColorAlways
+	}
+}
@@ -0,0 +1,248 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestColorPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    ColorMode // of the logger
+		wrap    func(w io.Writer) io.Writer
+		colored bool
+	}{
+		{"plain", ColorAlways, Plain, false},
+		{"colored", ColorNever, Colored, true},
+		{"logger mode", ColorAlways, func(w io.Writer) io.Writer { return w }, true},
+		{"outermost wins", ColorNever, func(w io.Writer) io.Writer { return Colored(Plain(w)) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			lg := NewLogger("TEST", WithWriters(tt.wrap(buf)), WithSync(true), WithColorMode(tt.mode), WithPrintTime(false))
+			lg.Info("GET done")
+			lg.Close()
+			if got := strings.Contains(buf.String(), "\033["); got != tt.colored {
+				t.Fatalf("colored = %v, want %v: %q", got, tt.colored, buf)
+			}
+		})
+	}
+}
+
+func TestColorPoliciesFanOut(t *testing.T) {
+	term, file := &syncBuffer{}, &syncBuffer{}
+	lg := NewLogger("TEST", WithWriters(Colored(term), Plain(file)), WithColor(Blue), WithPrintTime(false))
+	lg.Warn("disk FAIL")
+	lg.WithField("k", 1).Error("error again")
+	lg.Close()
+
+	want := "[TEST] [W] ? disk FAIL\n[TEST] <E> ! error again k=1\n"
+	if file.String() != want {
+		t.Fatalf("file got %q, want %q", file, want)
+	}
+	if !strings.Contains(term.String(), string(Blue)+"[TEST]") || stripANSI(term.String()) != want {
+		t.Fatalf("terminal got %q", term)
+	}
+}
+
+func TestLevelRouting(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		log   func(lg *Logger)
+		err   bool // routed to the error stream
+	}{
+		{LevelPrint, func(lg *Logger) { lg.Print("m") }, false},
+		{LevelTrace, func(lg *Logger) { lg.Trace("m") }, false},
+		{LevelDebug, func(lg *Logger) { lg.Debug("m") }, false},
+		{LevelInfo, func(lg *Logger) { lg.Info("m") }, false},
+		{LevelWarn, func(lg *Logger) { lg.Warn("m") }, true},
+		{LevelError, func(lg *Logger) { lg.Error("m") }, true},
+		{LevelFatal, func(lg *Logger) { lg.Fatal("m") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			out, errs := &syncBuffer{}, &syncBuffer{}
+			lg := NewLogger("TEST", WithSync(true), WithNoColor(), WithPrintTime(false), WithLevel(LevelTrace),
+				WithWriters(Levels(out, LevelTrace, LevelWarn-1), MinLevel(errs, LevelWarn)))
+			defer lg.Close()
+			lg.SetExitFunc(func(int) {})
+
+			tt.log(lg)
+			got, other := out.String(), errs.String()
+			if tt.err {
+				got, other = other, got
+			}
+			if !strings.HasSuffix(got, "m\n") || other != "" {
+				t.Fatalf("stdout %q, stderr %q", out, errs)
+			}
+		})
+	}
+}
+
+func TestLevelRoutingOrder(t *testing.T) {
+	out, errs := &syncBuffer{}, &syncBuffer{}
+	lg := NewLogger("TEST", WithNoColor(), WithPrintTime(false),
+		WithWriters(Levels(out, LevelTrace, LevelWarn-1), MinLevel(errs, LevelWarn)))
+	for i := 0; i < 20; i++ {
+		if i%3 == 0 {
+			lg.Warnf("%d", i)
+		} else {
+			lg.Infof("%d", i)
+		}
+	}
+	lg.Close()
+
+	var wantOut, wantErr strings.Builder
+	for i := 0; i < 20; i++ {
+		if i%3 == 0 {
+			fmt.Fprintf(&wantErr, "[TEST] [W] ? %d\n", i)
+		} else {
+			fmt.Fprintf(&wantOut, "[TEST] [I]   %d\n", i)
+		}
+	}
+	if out.String() != wantOut.String() || errs.String() != wantErr.String() {
+		t.Fatalf("stdout:\n%s\nstderr:\n%s", out, errs)
+	}
+}
+
+func TestSplitStdStreams(t *testing.T) {
+	sinks, raw := newSinks(SplitStdStreams())
+	if raw[0] != os.Stdout || raw[1] != os.Stderr {
+		t.Fatalf("writers = %v", raw)
+	}
+	for _, level := range []LogLevel{LevelPrint, LevelTrace, LevelInfo, LevelWarn, LevelFatal, LevelPanic} {
+		if sinks[0].accepts(level) == (level >= LevelWarn) || sinks[1].accepts(level) != (level >= LevelWarn) {
+			t.Errorf("%v routed to the wrong stream", level)
+		}
+	}
+}
+
+func TestAddRemoveWriter(t *testing.T) {
+	first := &syncBuffer{}
+	w := &slowWriter{syncBuffer: syncBuffer{}, delay: time.Millisecond}
+	lg := NewLogger("TEST", WithWriters(w), WithNoColor(), WithPrintTime(false))
+
+	lg.Info("queued")
+	h := lg.AddWriter(first)
+	lg.Info("added")
+	lg.RemoveWriter(h)
+	lg.Info("removed")
+	out := lg.SetOutput(first)
+	lg.Info("replaced")
+	lg.RemoveWriter(out)
+	lg.Info("nowhere")
+	lg.Close()
+
+	if got := w.String(); got != "[TEST] [I]   queued\n[TEST] [I]   added\n[TEST] [I]   removed\n" {
+		t.Fatalf("original writer got %q", got)
+	}
+	if got := first.String(); got != "[TEST] [I]   added\n[TEST] [I]   replaced\n" {
+		t.Fatalf("added writer got %q", got)
+	}
+}
+
+// Run with -race
+func TestAddWriterConcurrent(t *testing.T) {
+	lg, _ := newTestLogger(t)
+	async := NewLogger("TEST", WithWriters(&syncBuffer{}))
+	defer async.Close()
+
+	var wg sync.WaitGroup
+	for _, l := range []*Logger{lg, async} {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				l.Info("x")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				h := l.AddWriter(&syncBuffer{})
+				l.RemoveWriter(h)
+			}
+			l.SetOutput(&syncBuffer{})
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFormatWriters(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 5, 7, 0, time.UTC)
+	const (
+		text     = "[API] 2026/10/14 09:05:07 [I]   GET /users status=200\n"
+		jsonLine = `{"time":"2026-10-14T09:05:07Z","level":"info","module":"API","msg":"GET /users","status":200}` + "\n"
+		logfmt   = "time=2026-10-14T09:05:07Z level=info module=API msg=\"GET /users\" status=200\n"
+	)
+	tests := []struct {
+		name   string
+		format Format // of the logger
+		wrap   func(w io.Writer) io.Writer
+		want   string
+	}{
+		{"text to", FormatJSON, TextTo, text},
+		{"json to", FormatText, JSONTo, jsonLine},
+		{"logfmt to", FormatText, LogfmtTo, logfmt},
+		{"logger text", FormatText, func(w io.Writer) io.Writer { return w }, text},
+		{"logger json", FormatJSON, func(w io.Writer) io.Writer { return w }, jsonLine},
+		{"outermost wins", FormatText, func(w io.Writer) io.Writer { return JSONTo(LogfmtTo(w)) }, jsonLine},
+		{"through policies", FormatText, func(w io.Writer) io.Writer { return Plain(MinLevel(JSONTo(w), LevelInfo)) }, jsonLine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, other := &syncBuffer{}, &syncBuffer{}
+			lg := NewLogger("API", WithWriters(tt.wrap(buf), other), WithSync(true), WithNoColor(), WithFormat(tt.format))
+			lg.SetClock(func() time.Time { return now })
+			lg.WithField("status", 200).Info("GET /users")
+			lg.Close()
+
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+			// the unwrapped writer keeps the logger's format
+			want := text
+			if tt.format == FormatJSON {
+				want = jsonLine
+			}
+			if got := other.String(); got != want {
+				t.Fatalf("other writer got %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatWritersLevels(t *testing.T) {
+	console, file := &syncBuffer{}, &syncBuffer{}
+	lg := New("API", Blue, MinLevel(TextTo(console), LevelInfo), JSONTo(MinLevel(file, LevelDebug)))
+	lg.SetColorMode(ColorNever)
+	lg.SetPrintTime(false)
+	lg.SetLevel(LevelTrace)
+	lg.Trace("trace")
+	lg.Debug("debug")
+	lg.Info("info")
+	lg.Warn("warn")
+	lg.Close()
+
+	if got, want := console.String(), "[API] [I]   info\n[API] [W] ? warn\n"; got != want {
+		t.Errorf("console got %q, want %q", got, want)
+	}
+	var levels []string
+	for _, line := range strings.Split(strings.TrimSpace(file.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		levels = append(levels, fmt.Sprint(m["level"], ":", m["msg"]))
+	}
+	if got, want := strings.Join(levels, " "), "debug:debug info:info warn:warn"; got != want {
+		t.Errorf("file got %s, want %s", got, want)
+	}
+}
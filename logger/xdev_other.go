@@ -0,0 +1,14 @@
+//go:build !plan9
+
+package logger
+
+import (
+	"errors"
+	"syscall"
+)
+
+// crossDevice reports whether a rename failed because the paths are on
+// different devices
+func crossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
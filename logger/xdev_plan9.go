@@ -0,0 +1,6 @@
+package logger
+
+// crossDevice is always false on Plan 9, which has no EXDEV
+func crossDevice(err error) bool {
+	return false
+}